@@ -27,16 +27,38 @@ provider "keboola-management" {
 # ------------------------------------------------------------------------------
 
 resource "keboola-management_backend" "example" {
-  backend   = "snowflake"
-  host      = "example.snowflakecomputing.com"
-  username  = "terraform_user"
-  password  = "supersecret" # Use a secure method for real deployments
-  region    = "us-east-1"
-  owner     = "aws-account-owner"
-  warehouse = "COMPUTE_WH"
-  # database  = "optional-db" # Uncomment for Synapse/Teradata
-  # use_synapse_managed_identity = "false" # Optional for Synapse
-  # use_dynamic_backends = true # Optional for supported backends
+  backend          = "snowflake"
+  host             = "example.snowflakecomputing.com"
+  username         = "terraform_user"
+  password         = "supersecret" # Write-only, never stored in the state (Terraform 1.11+)
+  password_version = 1             # Increase to send a new password
+  region           = "us-east-1"
+  owner            = "aws-account-owner"
+  warehouse        = "COMPUTE_WH" # Required for Snowflake only
+  # use_dynamic_backends = true # Optional for Snowflake only
+}
+
+# Engine-specific resources validate the attributes of each engine
+resource "keboola-management_backend_snowflake" "example" {
+  host                 = "example.snowflakecomputing.com"
+  username             = "terraform_user"
+  password             = "supersecret" # Write-only, never stored in the state (Terraform 1.11+)
+  password_version     = 1             # Increase to send a new password
+  region               = "us-east-1"
+  owner                = "aws-account-owner"
+  warehouse            = "COMPUTE_WH"
+  use_dynamic_backends = true
+}
+
+resource "keboola-management_backend_synapse" "example" {
+  host                         = "example.sql.azuresynapse.net"
+  username                     = "terraform_user"
+  password                     = "supersecret" # Write-only, never stored in the state (Terraform 1.11+)
+  password_version             = 1             # Increase to send a new password
+  region                       = "westeurope"
+  owner                        = "azure-account-owner"
+  database                     = "KEBOOLA"
+  use_synapse_managed_identity = false
 }
 
 resource "keboola-management_backend_bigquery" "example" {
@@ -59,11 +81,12 @@ resource "keboola-management_backend_bigquery" "example" {
 }
 
 resource "keboola_file_storage_s3" "example" {
-  aws_key      = "AKIA..."
-  aws_secret   = "..." # sensitive
-  files_bucket = "my-bucket"
-  region       = "eu-central-1"
-  owner        = "my-aws-account-id"
+  aws_key            = "AKIA..."
+  aws_secret         = "..." # Write-only, never stored in the state
+  aws_secret_version = 1     # The API cannot rotate the secret, a change fails at plan time
+  files_bucket       = "my-bucket"
+  region             = "eu-central-1"
+  owner              = "my-aws-account-id"
 }
 
 resource "keboola_file_storage_gcs" "example" {
@@ -86,8 +109,9 @@ resource "keboola_file_storage_gcs" "example" {
 }
 
 resource "keboola_file_storage_azure_blob" "example" {
-  account_name   = "myazureaccount"
-  account_key    = "..." # sensitive
-  owner          = "azure-account-owner"
-  container_name = "my-container" # optional
+  account_name        = "myazureaccount"
+  account_key         = "..." # Write-only, never stored in the state
+  account_key_version = 1     # The API cannot rotate the key, a change fails at plan time
+  owner               = "azure-account-owner"
+  container_name      = "my-container" # optional
 } 
\ No newline at end of file
@@ -19,8 +19,8 @@ provider "keboola-management" {
 
 resource "keboola-management_maintainer" "full" {
   name                            = "Full Example Maintainer"
-  default_connection_snowflake_id = "1"
-  default_file_storage_id         = "1"
+  default_connection_snowflake_id = 1
+  default_file_storage_id         = 1
   zendesk_url                     = "https://example.zendesk.com"
 }
 
@@ -37,7 +37,7 @@ resource "keboola-management_project" "example" {
   organization_id             = keboola-management_organization.example.id # Reference to the organization resource
   type                        = "production"                               # or poc, demo
   default_backend             = "snowflake"                                # or redshift
-  data_retention_time_in_days = "7"                                        # optional, e.g. 7 days
+  data_retention_time_in_days = 7                                          # optional, e.g. 7 days
 }
 
 # Example: Project storage token (new pattern)
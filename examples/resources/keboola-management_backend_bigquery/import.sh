@@ -0,0 +1,3 @@
+# The BigQuery backend is imported by its ID.
+# The private key is write-only and is not imported, it is sent again when credentials.private_key_version is set or changed
+terraform import keboola-management_backend_bigquery.example 123
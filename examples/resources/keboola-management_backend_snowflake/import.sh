@@ -0,0 +1,3 @@
+# The Snowflake backend is imported by its ID.
+# The password is write-only and is not imported, it is sent again when password_version is set or changed
+terraform import keboola-management_backend_snowflake.example 123
@@ -0,0 +1,3 @@
+# The Azure Blob file storage is imported by its ID.
+# The account key is write-only and is not imported, account_key_version set after import is only recorded
+terraform import keboola-management_file_storage_azure_blob.example 123
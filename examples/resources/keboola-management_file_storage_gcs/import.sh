@@ -0,0 +1,3 @@
+# The GCS file storage is imported by its ID.
+# The private key is write-only and is not imported, gcs_credentials.private_key_version set after import is only recorded
+terraform import keboola-management_file_storage_gcs.example 123
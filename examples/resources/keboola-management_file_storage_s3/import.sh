@@ -0,0 +1,3 @@
+# The AWS S3 file storage is imported by its ID.
+# The AWS secret is write-only and is not imported, aws_secret_version set after import is only recorded
+terraform import keboola-management_file_storage_s3.example 123
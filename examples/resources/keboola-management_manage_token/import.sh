@@ -0,0 +1,3 @@
+# Manage tokens are imported by the token ID,
+# the token value is returned only on creation and stays empty after import
+terraform import keboola-management_manage_token.example 123
@@ -0,0 +1,2 @@
+# Project features are imported by the project ID and the feature name
+terraform import keboola-management_project_feature.example 123:queuev2
@@ -0,0 +1,2 @@
+# Project invitations are imported by the project ID and the invitation ID
+terraform import keboola-management_project_invitation.example 123:456
@@ -0,0 +1,3 @@
+# Project tokens are imported by the project ID and the token ID,
+# the token value is returned only on creation and stays empty after import
+terraform import keboola-management_project_token.example 123:456
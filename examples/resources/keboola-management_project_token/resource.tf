@@ -0,0 +1,15 @@
+resource "keboola-management_project_token" "example" {
+  project_id         = keboola-management_project.example.id
+  description        = "Extractor token"
+  can_manage_buckets = true
+
+  # Replace the token every 90 days, a week before the rotation is due
+  rotation_days      = 90
+  rotate_before_days = 7
+
+  # Create the new token before the old one is deleted,
+  # otherwise consumers of the token fail until they get the new value
+  lifecycle {
+    create_before_destroy = true
+  }
+}
@@ -0,0 +1,71 @@
+package keboola
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// tokenCommandCache holds the output of token commands, so each command runs once per provider run.
+var tokenCommandCache sync.Map
+
+// readTokenFile reads the token from a file and trims surrounding whitespace.
+func readTokenFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read token file %q: %w", path, err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("token file %q is empty", path)
+	}
+	return token, nil
+}
+
+// tokenFromEnv returns the token from KBC_MANAGE_TOKEN, or read from the KBC_MANAGE_TOKEN_FILE file.
+// It returns an empty token if neither is set.
+func tokenFromEnv() (string, error) {
+	if token := os.Getenv(KbcToken); token != "" { //nolint: forbidigo
+		return token, nil
+	}
+	if path := os.Getenv(KbcTokenFile); path != "" { //nolint: forbidigo
+		return readTokenFile(path)
+	}
+	return "", nil
+}
+
+// runTokenCommand runs the command through the system shell and returns its trimmed standard output.
+// The result is cached, so the command is executed only once per provider run.
+func runTokenCommand(ctx context.Context, command string) (string, error) {
+	if cached, ok := tokenCommandCache.Load(command); ok {
+		return cached.(string), nil
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Stdout is never included in the error, it may contain a partial token
+		return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("token command returned no output")
+	}
+
+	tokenCommandCache.Store(command, token)
+	return token, nil
+}
@@ -0,0 +1,53 @@
+package keboola
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTokenFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(path, []byte("  my-token\n"), 0o600))
+	token, err := readTokenFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "my-token", token)
+
+	empty := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(empty, []byte("\n"), 0o600))
+	_, err = readTokenFile(empty)
+	assert.ErrorContains(t, err, "is empty")
+
+	_, err = readTokenFile(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestRunTokenCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell commands in this test require sh")
+	}
+	ctx := context.Background()
+
+	// The command output is cached, so a second run returns the first value
+	counter := filepath.Join(t.TempDir(), "counter")
+	command := "echo x >> " + counter + " && echo \" token-from-command \""
+	token, err := runTokenCommand(ctx, command)
+	require.NoError(t, err)
+	assert.Equal(t, "token-from-command", token)
+	token, err = runTokenCommand(ctx, command)
+	require.NoError(t, err)
+	assert.Equal(t, "token-from-command", token)
+	runs, err := os.ReadFile(counter)
+	require.NoError(t, err)
+	assert.Equal(t, "x\n", string(runs))
+
+	_, err = runTokenCommand(ctx, "echo oops >&2; exit 3")
+	assert.ErrorContains(t, err, "oops")
+}
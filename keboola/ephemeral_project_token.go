@@ -0,0 +1,196 @@
+package keboola
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	sdk "github.com/keboola/keboola-sdk-go/v2/pkg/keboola"
+)
+
+// projectTokenPrivateKey is the private data key holding the token to be deleted on close.
+const projectTokenPrivateKey = "project_token"
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ ephemeral.EphemeralResource              = &projectTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &projectTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &projectTokenEphemeralResource{}
+)
+
+// NewProjectTokenEphemeralResource returns a new keboola_project_token ephemeral resource.
+func NewProjectTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &projectTokenEphemeralResource{}
+}
+
+// projectTokenEphemeralResource implements the keboola_project_token ephemeral resource.
+type projectTokenEphemeralResource struct {
+	client *Client
+}
+
+// projectTokenEphemeralResourceModel maps the ephemeral resource schema data.
+type projectTokenEphemeralResourceModel struct {
+	ID types.String `tfsdk:"id"`
+	projectTokenSettingsModel
+	ExpiresIn types.Int64  `tfsdk:"expires_in"`
+	Token     types.String `tfsdk:"token"`
+}
+
+// projectTokenPrivateData is stored in the private data between open and close.
+type projectTokenPrivateData struct {
+	ID    string `json:"id"`
+	Token string `json:"token"`
+}
+
+// Configure adds the provider configured client to the ephemeral resource.
+func (r *projectTokenEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, _ *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.client = req.ProviderData.(*Client)
+}
+
+// Metadata returns the ephemeral resource type name.
+func (r *projectTokenEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project_token"
+}
+
+// Schema defines the schema for the ephemeral resource.
+func (r *projectTokenEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Creates a short-lived Keboola project storage token for the duration of a Terraform run. " +
+			"The token is never stored in the plan or state and is deleted when Terraform no longer needs it.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Token ID.",
+				Computed:    true,
+			},
+			"project_id": schema.StringAttribute{
+				Description: "ID of the Keboola project.",
+				Required:    true,
+				Validators:  numericIDValidators(),
+			},
+			"description": schema.StringAttribute{
+				Description: "Token description.",
+				Optional:    true,
+			},
+			"can_manage_buckets": schema.BoolAttribute{
+				Description: "Token has full permissions on tabular storage.",
+				Optional:    true,
+			},
+			"can_read_all_file_uploads": schema.BoolAttribute{
+				Description: "Token has full permissions to files staging.",
+				Optional:    true,
+			},
+			"can_purge_trash": schema.BoolAttribute{
+				Description: "Allows permanently removing deleted configurations.",
+				Optional:    true,
+			},
+			"expires_in": schema.Int64Attribute{
+				Description: "Token lifetime in seconds, the token expires even if it could not be deleted.",
+				Required:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"bucket_permissions": schema.MapAttribute{
+				Description: "Map of bucket IDs to the permission granted to the token, `read` or `write`, e.g., {\"in.c-main\": \"read\"}.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators:  projectTokenBucketPermissionsValidators(),
+			},
+			"component_access": schema.ListAttribute{
+				Description: "List of component IDs to grant access for component configurations.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"token": schema.StringAttribute{
+				Description: "Token value.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+// Open creates the storage token.
+func (r *projectTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data projectTokenEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	expiresIn := float32(data.ExpiresIn.ValueInt64())
+	tokenBody, diags := data.createRequest(ctx, &expiresIn)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tokenResp, err := r.client.createProjectToken(ctx, data.ProjectID.ValueString(), tokenBody)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating storage token", "Could not create storage token: "+err.Error())
+		return
+	}
+
+	data.ID = types.StringValue(tokenResp.GetId())
+	data.Token = types.StringValue(tokenResp.GetToken())
+
+	// The token value is needed to delete the token on close
+	private, err := json.Marshal(projectTokenPrivateData{ID: tokenResp.GetId(), Token: tokenResp.GetToken()})
+	if err != nil {
+		resp.Diagnostics.AddError("Error storing storage token", "Could not encode the token private data: "+err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, projectTokenPrivateKey, private)...)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// Close deletes the storage token via the Storage API.
+func (r *projectTokenEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	private, diags := req.Private.GetKey(ctx, projectTokenPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || private == nil {
+		return
+	}
+
+	var data projectTokenPrivateData
+	if err := json.Unmarshal(private, &data); err != nil {
+		resp.Diagnostics.AddError("Error reading storage token", "Could not decode the token private data: "+err.Error())
+		return
+	}
+
+	client, err := r.client.NewStorageAPI(ctx, data.Token)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating authorized API client",
+			"Could not create authorized API client: "+err.Error(),
+		)
+		return
+	}
+
+	_, err = client.DeleteTokenRequest(data.ID).Send(ctx)
+	var storageErr *sdk.StorageError
+	if errors.As(err, &storageErr) && (storageErr.StatusCode() == http.StatusUnauthorized || storageErr.StatusCode() == http.StatusNotFound) {
+		// The token has already expired or was deleted
+		tflog.Info(ctx, fmt.Sprintf("Storage token %s no longer exists, skipping deletion", data.ID))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting storage token",
+			"Could not delete storage token: "+err.Error(),
+		)
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("Storage token %s deleted", data.ID))
+}
@@ -0,0 +1,58 @@
+package keboola
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccProjectTokenEphemeralResource(t *testing.T) {
+	fake := newFakeAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		// The echo provider copies the ephemeral values to its state, so they can be checked
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"keboola-management": providerserver.NewProtocol6WithError(New()),
+			"echo":               echoprovider.NewProviderServer(),
+		},
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			// The token is created on open and deleted on close
+			{
+				Config: fake.providerConfig() + testAccProjectTokenEphemeralConfig(),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("description"), knownvalue.StringExact("tf-test-ephemeral")),
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("expires_in"), knownvalue.Int64Exact(600)),
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data").AtMapKey("token"), knownvalue.NotNull()),
+				},
+				Check: testAccCheckTokenCount(fake, 0),
+			},
+		},
+	})
+}
+
+func testAccProjectTokenEphemeralConfig() string {
+	return testAccProjectConfig("tf-test-project") + `
+ephemeral "keboola-management_project_token" "test" {
+  project_id         = keboola-management_project.test.id
+  description        = "tf-test-ephemeral"
+  can_manage_buckets = true
+  expires_in         = 600
+}
+
+provider "echo" {
+  data = ephemeral.keboola-management_project_token.test
+}
+
+resource "echo" "test" {}
+`
+}
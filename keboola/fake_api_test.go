@@ -0,0 +1,1408 @@
+package keboola
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeManageToken is the only Manage API token accepted by the fake API.
+const fakeManageToken = "fake-manage-token"
+
+// Kinds of objects stored by the fake API.
+const (
+	fakeManageTokens  = "manage-tokens"
+	fakeMaintainers   = "maintainers"
+	fakeOrganizations = "organizations"
+	fakeProjects      = "projects"
+	fakeTokens        = "tokens"
+	fakeInvitations   = "invitations"
+	fakeProjectUsers  = "project-users"
+	fakeBackends      = "backends"
+	fakeS3Storages    = "file-storage-s3"
+	fakeABSStorages   = "file-storage-abs"
+	fakeGCSStorages   = "file-storage-gcs"
+)
+
+// fakeAPI is a stateful in-memory implementation of the Manage API and of the few
+// Storage API endpoints used by the provider. Responses follow the SDK models,
+// so the provider decodes them the same way as responses of a real stack.
+type fakeAPI struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	lastID  int
+	objects map[string]map[string]*fakeObject
+}
+
+// fakeObject is a stored object, data is the JSON representation returned by the API.
+type fakeObject struct {
+	// parent is the ID of the owning maintainer, organization or project
+	parent string
+	data   map[string]interface{}
+	// secrets are the write-only fields, they are never returned by the API
+	secrets map[string]interface{}
+}
+
+// newFakeAPI starts the fake API, it is stopped at the end of the test.
+func newFakeAPI(t *testing.T) *fakeAPI {
+	t.Helper()
+
+	f := &fakeAPI{objects: make(map[string]map[string]*fakeObject)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /manage/tokens/verify", f.verifyManageToken)
+	mux.HandleFunc("POST /manage/tokens", f.createManageToken)
+	mux.HandleFunc("GET /manage/tokens", f.listHandler(fakeManageTokens, ""))
+	mux.HandleFunc("GET /manage/tokens/{id}", f.getHandler(fakeManageTokens))
+	mux.HandleFunc("DELETE /manage/tokens/{id}", f.deleteHandler(fakeManageTokens, ""))
+
+	mux.HandleFunc("POST /manage/maintainers", f.createMaintainer)
+	mux.HandleFunc("GET /manage/maintainers", f.listHandler(fakeMaintainers, ""))
+	mux.HandleFunc("GET /manage/maintainers/{id}", f.getHandler(fakeMaintainers))
+	mux.HandleFunc("PATCH /manage/maintainers/{id}", f.updateMaintainer)
+	mux.HandleFunc("DELETE /manage/maintainers/{id}", f.deleteHandler(fakeMaintainers, fakeOrganizations))
+
+	mux.HandleFunc("POST /manage/maintainers/{id}/organizations", f.createOrganization)
+	mux.HandleFunc("GET /manage/maintainers/{id}/organizations", f.listHandler(fakeOrganizations, fakeMaintainers))
+	mux.HandleFunc("GET /manage/organizations/{id}", f.organizationDetail)
+	mux.HandleFunc("PATCH /manage/organizations/{id}", f.updateOrganization)
+	mux.HandleFunc("DELETE /manage/organizations/{id}", f.deleteHandler(fakeOrganizations, fakeProjects))
+
+	mux.HandleFunc("POST /manage/organizations/{id}/projects", f.createProject)
+	mux.HandleFunc("GET /manage/organizations/{id}/projects", f.listHandler(fakeProjects, fakeOrganizations))
+	mux.HandleFunc("GET /manage/projects/{id}", f.getHandler(fakeProjects))
+	mux.HandleFunc("PUT /manage/projects/{id}", f.updateProject)
+	mux.HandleFunc("DELETE /manage/projects/{id}", f.deleteHandler(fakeProjects, ""))
+
+	mux.HandleFunc("POST /manage/projects/{id}/features", f.addProjectFeature)
+	mux.HandleFunc("DELETE /manage/projects/{id}/features/{feature}", f.removeProjectFeature)
+
+	mux.HandleFunc("POST /manage/projects/{id}/tokens", f.createToken)
+
+	mux.HandleFunc("GET /manage/projects/{id}/users", f.listHandler(fakeProjectUsers, fakeProjects))
+	mux.HandleFunc("PATCH /manage/projects/{id}/users/{user}", f.updateProjectUser)
+
+	mux.HandleFunc("POST /manage/projects/{id}/invitations", f.createInvitation)
+	mux.HandleFunc("GET /manage/projects/{id}/invitations", f.listHandler(fakeInvitations, fakeProjects, "id", "created", "expires", "reason", "user", "creator"))
+	mux.HandleFunc("GET /manage/projects/{id}/invitations/{invitation}", f.getChildHandler(fakeInvitations))
+	mux.HandleFunc("DELETE /manage/projects/{id}/invitations/{invitation}", f.deleteChildHandler(fakeInvitations))
+
+	mux.HandleFunc("POST /manage/storage-backend", f.createBackend)
+	mux.HandleFunc("GET /manage/storage-backend", f.listHandler(fakeBackends, ""))
+	mux.HandleFunc("GET /manage/storage-backend/{id}", f.backendDetail)
+	mux.HandleFunc("PATCH /manage/storage-backend/{id}", f.updateBackend)
+	mux.HandleFunc("DELETE /manage/storage-backend/{id}", f.deleteHandler(fakeBackends, ""))
+	mux.HandleFunc("POST /manage/storage-backend/bigquery", f.createBigQueryBackend)
+	mux.HandleFunc("PATCH /manage/storage-backend/bigquery/{id}", f.updateBigQueryBackend)
+
+	mux.HandleFunc("POST /manage/file-storage-s3", f.createS3Storage)
+	mux.HandleFunc("GET /manage/file-storage-s3", f.listHandler(fakeS3Storages, ""))
+	mux.HandleFunc("POST /manage/file-storage-abs", f.createABSStorage)
+	mux.HandleFunc("GET /manage/file-storage-abs", f.listHandler(fakeABSStorages, ""))
+	mux.HandleFunc("POST /manage/file-storage-gcs", f.createGCSStorage)
+	mux.HandleFunc("GET /manage/file-storage-gcs", f.listHandler(fakeGCSStorages, ""))
+	mux.HandleFunc("POST /manage/file-storage-s3/{id}/default", f.setDefaultStorage(fakeS3Storages))
+	mux.HandleFunc("POST /manage/file-storage-abs/{id}/default", f.setDefaultStorage(fakeABSStorages))
+
+	mux.HandleFunc("GET /v2/storage/{$}", f.storageIndex)
+	mux.HandleFunc("GET /v2/storage/tokens", f.listStorageTokens)
+	mux.HandleFunc("GET /v2/storage/tokens/verify", f.verifyStorageToken)
+	mux.HandleFunc("GET /v2/storage/tokens/{id}", f.storageTokenDetail)
+	mux.HandleFunc("PUT /v2/storage/tokens/{id}", f.updateStorageToken)
+	mux.HandleFunc("DELETE /v2/storage/tokens/{id}", f.deleteStorageToken)
+
+	f.server = httptest.NewServer(f.authenticate(mux))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// providerConfig returns a provider block pointing to the fake API.
+func (f *fakeAPI) providerConfig() string {
+	return fmt.Sprintf(`
+provider "keboola-management" {
+  api_url = %q
+  token   = %q
+}
+`, f.server.URL, fakeManageToken)
+}
+
+// client returns a provider client for tests calling the SDK directly.
+func (f *fakeAPI) client() *Client {
+	return newClient(f.server.URL, fakeManageToken, f.server.Client())
+}
+
+// get returns a copy of the stored object, tests use it to verify the remote state.
+func (f *fakeAPI) get(kind, id string) (map[string]interface{}, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[kind][id]
+	if !ok {
+		return nil, false
+	}
+	return copyJSONObject(obj.data), true
+}
+
+// count returns the number of stored objects of the kind.
+func (f *fakeAPI) count(kind string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.objects[kind])
+}
+
+// update modifies a stored object out of band, tests use it to simulate drift.
+func (f *fakeAPI) update(kind, id string, fn func(data map[string]interface{})) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[kind][id]
+	if ok {
+		fn(obj.data)
+	}
+	return ok
+}
+
+// remove deletes a stored object out of band.
+func (f *fakeAPI) remove(kind, id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.objects[kind][id]
+	delete(f.objects[kind], id)
+	return ok
+}
+
+// acceptInvitations accepts all pending invitations, the invited users become project members.
+func (f *fakeAPI) acceptInvitations() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	accepted := 0
+	for id, obj := range f.objects[fakeInvitations] {
+		delete(f.objects[fakeInvitations], id)
+		userID := f.nextID(fakeProjectUsers)
+		user := obj.data["user"].(map[string]interface{})
+		f.put(fakeProjectUsers, strconv.Itoa(userID), &fakeObject{parent: obj.parent, data: map[string]interface{}{
+			"id":       userID,
+			"name":     user["email"],
+			"email":    user["email"],
+			"features": []interface{}{},
+			"expires":  obj.data["expires"],
+			"created":  fakeTimestamp(time.Now()),
+			"reason":   obj.data["reason"],
+			"role":     obj.data["role"],
+			"status":   "active",
+			"invitor":  obj.data["creator"],
+			"approver": map[string]interface{}{},
+		}})
+		accepted++
+	}
+	return accepted
+}
+
+// authenticate rejects requests without a valid token.
+func (f *fakeAPI) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/manage/"):
+			if r.Header.Get("X-KBC-ManageApiToken") != fakeManageToken {
+				writeFakeError(w, http.StatusUnauthorized, "Invalid access token")
+				return
+			}
+		case r.URL.Path == "/v2/storage/":
+			// The index is public
+		case strings.HasPrefix(r.URL.Path, "/v2/storage/"):
+			if _, ok := f.storageToken(r); !ok {
+				writeFakeStorageError(w, http.StatusUnauthorized, "storage.tokenInvalid", "Invalid access token")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (f *fakeAPI) verifyManageToken(w http.ResponseWriter, _ *http.Request) {
+	writeFakeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":          1,
+		"description": "Fake manage token",
+		"created":     fakeTimestamp(time.Now()),
+		"scopes":      []string{},
+		"type":        "admin",
+		"creator":     map[string]interface{}{"id": 1, "name": "Fake Admin"},
+		"user":        map[string]interface{}{"id": 1, "name": "Fake Admin", "email": "admin@example.com"},
+	})
+}
+
+// createManageToken stores an application token, the token value is returned only in the response.
+func (f *fakeAPI) createManageToken(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	if stringValue(body["description"]) == "" {
+		writeFakeError(w, http.StatusBadRequest, "Description is required")
+		return
+	}
+
+	now := time.Now()
+	data := map[string]interface{}{
+		"description": body["description"],
+		"scopes":      []interface{}{},
+		"type":        "admin",
+		"created":     fakeTimestamp(now),
+		"expires":     nil,
+		"isExpired":   false,
+		"isDisabled":  false,
+	}
+	if v, ok := body["scopes"].([]interface{}); ok {
+		data["scopes"] = v
+	}
+	if v, ok := body["expiresIn"].(float64); ok && v > 0 {
+		data["expires"] = fakeTimestamp(now.Add(time.Duration(v) * time.Second))
+	}
+
+	response := f.store(fakeManageTokens, "", data)
+	response["token"] = fmt.Sprintf("%v-fake-manage-token", response["id"])
+	writeFakeJSON(w, http.StatusCreated, response)
+}
+
+func (f *fakeAPI) createMaintainer(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	if stringValue(body["name"]) == "" {
+		writeFakeError(w, http.StatusBadRequest, "Name is required")
+		return
+	}
+
+	data := map[string]interface{}{"created": fakeTimestamp(time.Now())}
+	setMaintainerFields(data, body)
+	writeFakeJSON(w, http.StatusCreated, f.store(fakeMaintainers, "", data))
+}
+
+func (f *fakeAPI) updateMaintainer(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	f.modify(w, fakeMaintainers, r.PathValue("id"), func(obj *fakeObject) {
+		setMaintainerFields(obj.data, body)
+	})
+}
+
+func setMaintainerFields(data, body map[string]interface{}) {
+	if v, ok := body["name"]; ok {
+		data["name"] = v
+	}
+	if v, ok := body["zendeskUrl"]; ok {
+		data["zendeskUrl"] = v
+	}
+	// IDs are sent as strings but returned as numbers
+	for _, field := range []string{
+		"defaultConnectionRedshiftId",
+		"defaultConnectionSnowflakeId",
+		"defaultConnectionSynapseId",
+		"defaultConnectionExasolId",
+		"defaultConnectionTeradataId",
+		"defaultFileStorageId",
+	} {
+		if v, ok := body[field]; ok {
+			setNumberField(data, field, v)
+		}
+	}
+}
+
+func (f *fakeAPI) createOrganization(w http.ResponseWriter, r *http.Request) {
+	maintainerID := r.PathValue("id")
+	if !f.exists(fakeMaintainers, maintainerID) {
+		writeFakeError(w, http.StatusNotFound, "Maintainer "+maintainerID+" not found")
+		return
+	}
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	data := map[string]interface{}{
+		"name":          body["name"],
+		"created":       fakeTimestamp(time.Now()),
+		"allowAutoJoin": true,
+		"mfaRequired":   false,
+	}
+	if v, ok := body["crmId"]; ok {
+		data["crmId"] = v
+	}
+	writeFakeJSON(w, http.StatusCreated, f.store(fakeOrganizations, maintainerID, data))
+}
+
+func (f *fakeAPI) updateOrganization(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	if maintainerID := stringValue(body["maintainerId"]); maintainerID != "" && !f.exists(fakeMaintainers, maintainerID) {
+		writeFakeError(w, http.StatusBadRequest, "Maintainer "+maintainerID+" not found")
+		return
+	}
+	f.modify(w, fakeOrganizations, r.PathValue("id"), func(obj *fakeObject) {
+		for _, field := range []string{"name", "crmId"} {
+			if v, ok := body[field]; ok {
+				obj.data[field] = v
+			}
+		}
+		for _, field := range []string{"allowAutoJoin", "mfaRequired"} {
+			if v, ok := body[field]; ok {
+				obj.data[field] = boolValue(v)
+			}
+		}
+		if v, ok := body["activityCenterProjectId"]; ok {
+			setNumberField(obj.data, "activityCenterProjectId", v)
+		}
+		if v := stringValue(body["maintainerId"]); v != "" {
+			obj.parent = v
+		}
+	})
+}
+
+func (f *fakeAPI) organizationDetail(w http.ResponseWriter, r *http.Request) {
+	data, ok := f.get(fakeOrganizations, r.PathValue("id"))
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "Organization "+r.PathValue("id")+" not found")
+		return
+	}
+	projects := f.list(fakeProjects, r.PathValue("id"))
+	for i, project := range projects {
+		projects[i] = pickFields(project.(map[string]interface{}), "id", "name", "created")
+	}
+	data["projects"] = projects
+	writeFakeJSON(w, http.StatusOK, data)
+}
+
+func (f *fakeAPI) createProject(w http.ResponseWriter, r *http.Request) {
+	organizationID := r.PathValue("id")
+	if !f.exists(fakeOrganizations, organizationID) {
+		writeFakeError(w, http.StatusNotFound, "Organization "+organizationID+" not found")
+		return
+	}
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	if stringValue(body["name"]) == "" || stringValue(body["type"]) == "" {
+		writeFakeError(w, http.StatusBadRequest, "Name and type are required")
+		return
+	}
+
+	data := map[string]interface{}{
+		"name":           body["name"],
+		"type":           body["type"],
+		"region":         "us-east-1",
+		"created":        fakeTimestamp(time.Now()),
+		"defaultBackend": "snowflake",
+		"features":       []interface{}{},
+		"isDisabled":     false,
+		"organization":   map[string]interface{}{"id": numberValue(organizationID)},
+	}
+	if v, ok := body["defaultBackend"]; ok {
+		data["defaultBackend"] = v
+	}
+	if v, ok := body["dataRetentionTimeInDays"]; ok {
+		setNumberField(data, "dataRetentionTimeInDays", v)
+	}
+	writeFakeJSON(w, http.StatusCreated, f.store(fakeProjects, organizationID, data))
+}
+
+func (f *fakeAPI) updateProject(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	f.modify(w, fakeProjects, r.PathValue("id"), func(obj *fakeObject) {
+		for _, field := range []string{"name", "type", "defaultBackend"} {
+			if v, ok := body[field]; ok {
+				obj.data[field] = v
+			}
+		}
+		for _, field := range []string{"expirationDays", "billedMonthlyPrice", "dataRetentionTimeInDays"} {
+			if v, ok := body[field]; ok {
+				setNumberField(obj.data, field, v)
+			}
+		}
+	})
+}
+
+func (f *fakeAPI) addProjectFeature(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	feature := stringValue(body["feature"])
+	if feature == "" {
+		writeFakeError(w, http.StatusBadRequest, "Feature is required")
+		return
+	}
+	f.modify(w, fakeProjects, r.PathValue("id"), func(obj *fakeObject) {
+		features, _ := obj.data["features"].([]interface{})
+		for _, item := range features {
+			if item == feature {
+				return
+			}
+		}
+		obj.data["features"] = append(features, feature)
+	})
+}
+
+func (f *fakeAPI) removeProjectFeature(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	obj, ok := f.objects[fakeProjects][r.PathValue("id")]
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "Project "+r.PathValue("id")+" not found")
+		return
+	}
+	features, _ := obj.data["features"].([]interface{})
+	for i, item := range features {
+		if item == r.PathValue("feature") {
+			obj.data["features"] = append(features[:i:i], features[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeFakeError(w, http.StatusNotFound, "Feature "+r.PathValue("feature")+" is not assigned to the project")
+}
+
+func (f *fakeAPI) createToken(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("id")
+	if !f.exists(fakeProjects, projectID) {
+		writeFakeError(w, http.StatusNotFound, "Project "+projectID+" not found")
+		return
+	}
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	data := map[string]interface{}{
+		"description":           body["description"],
+		"created":               fakeTimestamp(now),
+		"refreshed":             fakeTimestamp(now),
+		"expires":               nil,
+		"isMasterToken":         false,
+		"isExpired":             false,
+		"isDisabled":            false,
+		"canManageBuckets":      boolValue(body["canManageBuckets"]),
+		"canManageTokens":       boolValue(body["canManageTokens"]),
+		"canReadAllFileUploads": boolValue(body["canReadAllFileUploads"]),
+		"canPurgeTrash":         boolValue(body["canPurgeTrash"]),
+		"bucketPermissions":     map[string]interface{}{},
+		"componentAccess":       []interface{}{},
+		"owner":                 map[string]interface{}{"id": numberValue(projectID), "name": "Project " + projectID},
+	}
+	if v, ok := body["expiresIn"].(float64); ok && v > 0 {
+		data["expires"] = fakeTimestamp(now.Add(time.Duration(v) * time.Second))
+	}
+	if v, ok := body["bucketPermissions"].(map[string]interface{}); ok {
+		data["bucketPermissions"] = v
+	}
+	if v, ok := body["componentAccess"].([]interface{}); ok {
+		data["componentAccess"] = v
+	}
+
+	f.mu.Lock()
+	f.lastID++
+	id := strconv.Itoa(f.lastID)
+	data["id"] = id
+	data["token"] = fmt.Sprintf("%s-%s-fake-storage-token", projectID, id)
+	f.put(fakeTokens, id, &fakeObject{parent: projectID, data: data})
+	response := copyJSONObject(data)
+	f.mu.Unlock()
+
+	writeFakeJSON(w, http.StatusCreated, response)
+}
+
+func (f *fakeAPI) createInvitation(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("id")
+	if !f.exists(fakeProjects, projectID) {
+		writeFakeError(w, http.StatusNotFound, "Project "+projectID+" not found")
+		return
+	}
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	email := stringValue(body["email"])
+	if email == "" {
+		writeFakeError(w, http.StatusBadRequest, "Email is required")
+		return
+	}
+
+	now := time.Now()
+	data := map[string]interface{}{
+		"created": fakeTimestamp(now),
+		"expires": nil,
+		"reason":  "",
+		"role":    "admin",
+		"user":    map[string]interface{}{"email": email},
+		"creator": map[string]interface{}{"id": 1, "name": "Fake Admin", "email": "admin@example.com"},
+	}
+	if v := stringValue(body["role"]); v != "" {
+		data["role"] = v
+	}
+	if v, ok := body["reason"]; ok {
+		data["reason"] = v
+	}
+	if v, ok := body["expirationSeconds"].(float64); ok && v > 0 {
+		data["expires"] = fakeTimestamp(now.Add(time.Duration(v) * time.Second))
+	}
+	writeFakeJSON(w, http.StatusCreated, f.store(fakeInvitations, projectID, data))
+}
+
+func (f *fakeAPI) updateProjectUser(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	f.mu.Lock()
+	var data map[string]interface{}
+	if obj, ok := f.objects[fakeProjectUsers][r.PathValue("user")]; ok && obj.parent == r.PathValue("id") {
+		if v := stringValue(body["role"]); v != "" {
+			obj.data["role"] = v
+		}
+		data = copyJSONObject(obj.data)
+	}
+	f.mu.Unlock()
+
+	if data == nil {
+		writeFakeError(w, http.StatusNotFound, "User "+r.PathValue("user")+" not found")
+		return
+	}
+	writeFakeJSON(w, http.StatusOK, data)
+}
+
+// Fields of the strictly decoded backend responses.
+var (
+	backendResponseFields  = []string{"id", "host", "backend", "region"}
+	bigQueryResponseFields = []string{"id", "region", "backend", "credentials", "folderId"}
+)
+
+func (f *fakeAPI) createBackend(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	for _, field := range []string{"backend", "host", "username", "region", "owner"} {
+		if stringValue(body[field]) == "" {
+			writeFakeError(w, http.StatusBadRequest, "Field "+field+" is required")
+			return
+		}
+	}
+	secrets := backendSecrets(body)
+	if len(secrets) == 0 {
+		writeFakeError(w, http.StatusBadRequest, "Field password is required")
+		return
+	}
+
+	// The secrets are never returned
+	data := map[string]interface{}{"created": fakeTimestamp(time.Now())}
+	for _, field := range []string{"backend", "host", "username", "region", "owner", "warehouse", "database", "useSynapseManagedIdentity", "useDynamicBackends"} {
+		if v, ok := body[field]; ok {
+			data[field] = v
+		}
+	}
+	data = f.store(fakeBackends, "", data)
+	f.setSecrets(fakeBackends, stringValue(data["id"]), secrets)
+	writeFakeJSON(w, http.StatusCreated, pickFields(data, backendResponseFields...))
+}
+
+// backendSecrets returns the credentials of a backend request.
+func backendSecrets(body map[string]interface{}) map[string]interface{} {
+	if stringValue(body["password"]) == "" {
+		return nil
+	}
+	return map[string]interface{}{"password": body["password"]}
+}
+
+func (f *fakeAPI) backendDetail(w http.ResponseWriter, r *http.Request) {
+	data, ok := f.get(fakeBackends, r.PathValue("id"))
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "Backend "+r.PathValue("id")+" not found")
+		return
+	}
+	// The SDK decodes the detail as a list
+	writeFakeJSON(w, http.StatusOK, []interface{}{data})
+}
+
+func (f *fakeAPI) updateBackend(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	f.modify(w, fakeBackends, r.PathValue("id"), func(obj *fakeObject) {
+		for _, field := range []string{"username", "useDynamicBackends"} {
+			if v, ok := body[field]; ok {
+				obj.data[field] = v
+			}
+		}
+		if secrets := backendSecrets(body); len(secrets) > 0 {
+			obj.secrets = secrets
+		}
+	}, backendResponseFields...)
+}
+
+func (f *fakeAPI) createBigQueryBackend(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	for _, field := range []string{"owner", "folderId", "region"} {
+		if stringValue(body[field]) == "" {
+			writeFakeError(w, http.StatusBadRequest, "Field "+field+" is required")
+			return
+		}
+	}
+
+	data := map[string]interface{}{
+		"backend": "bigquery",
+		"owner":   body["owner"],
+		"region":  body["region"],
+		"created": fakeTimestamp(time.Now()),
+	}
+	setNumberField(data, "folderId", body["folderId"])
+	if v, ok := body["credentials"].(map[string]interface{}); ok {
+		data["credentials"] = withoutPrivateKey(v)
+	}
+	data = f.store(fakeBackends, "", data)
+	f.setSecrets(fakeBackends, stringValue(data["id"]), storageSecrets(body))
+	writeFakeJSON(w, http.StatusCreated, pickFields(data, bigQueryResponseFields...))
+}
+
+func (f *fakeAPI) updateBigQueryBackend(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	f.modify(w, fakeBackends, r.PathValue("id"), func(obj *fakeObject) {
+		if v, ok := body["credentials"].(map[string]interface{}); ok {
+			obj.data["credentials"] = withoutPrivateKey(v)
+			obj.secrets = storageSecrets(body)
+		}
+	}, bigQueryResponseFields...)
+}
+
+func (f *fakeAPI) createS3Storage(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	for _, field := range []string{"awsKey", "awsSecret", "filesBucket", "region", "owner"} {
+		if stringValue(body[field]) == "" {
+			writeFakeError(w, http.StatusBadRequest, "Field "+field+" is required")
+			return
+		}
+	}
+
+	// The secret is never returned
+	data := map[string]interface{}{
+		"awsKey":      body["awsKey"],
+		"filesBucket": body["filesBucket"],
+		"region":      body["region"],
+		"owner":       body["owner"],
+		"provider":    "aws",
+		"isDefault":   false,
+		"created":     fakeTimestamp(time.Now()),
+	}
+	data = f.store(fakeS3Storages, "", data)
+	f.setSecrets(fakeS3Storages, stringValue(data["id"]), storageSecrets(body))
+	writeFakeJSON(w, http.StatusCreated, fileStorageCreateResponse(data))
+}
+
+func (f *fakeAPI) createABSStorage(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	for _, field := range []string{"accountName", "accountKey", "owner"} {
+		if stringValue(body[field]) == "" {
+			writeFakeError(w, http.StatusBadRequest, "Field "+field+" is required")
+			return
+		}
+	}
+
+	// The account key is never returned
+	data := map[string]interface{}{
+		"accountName": body["accountName"],
+		"owner":       body["owner"],
+		"provider":    "azure",
+		"isDefault":   false,
+		"created":     fakeTimestamp(time.Now()),
+	}
+	if v, ok := body["containerName"]; ok {
+		data["containerName"] = v
+	}
+	data = f.store(fakeABSStorages, "", data)
+	f.setSecrets(fakeABSStorages, stringValue(data["id"]), storageSecrets(body))
+	writeFakeJSON(w, http.StatusCreated, fileStorageCreateResponse(data))
+}
+
+func (f *fakeAPI) createGCSStorage(w http.ResponseWriter, r *http.Request) {
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+	for _, field := range []string{"filesBucket", "owner", "region"} {
+		if stringValue(body[field]) == "" {
+			writeFakeError(w, http.StatusBadRequest, "Field "+field+" is required")
+			return
+		}
+	}
+
+	data := map[string]interface{}{
+		"filesBucket": body["filesBucket"],
+		"owner":       body["owner"],
+		"region":      body["region"],
+		"provider":    "gcp",
+		"isDefault":   false,
+		"created":     fakeTimestamp(time.Now()),
+	}
+	if v, ok := body["gcsCredentials"].(map[string]interface{}); ok {
+		data["gcsCredentials"] = withoutPrivateKey(v)
+	}
+	data = f.store(fakeGCSStorages, "", data)
+	f.setSecrets(fakeGCSStorages, stringValue(data["id"]), storageSecrets(body))
+	writeFakeJSON(w, http.StatusCreated, data)
+}
+
+// setDefaultStorage marks the storage as the default, the previous default of the provider is unset.
+func (f *fakeAPI) setDefaultStorage(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		f.mu.Lock()
+		obj, ok := f.objects[kind][id]
+		var data map[string]interface{}
+		if ok {
+			for _, other := range f.objects[kind] {
+				other.data["isDefault"] = false
+			}
+			obj.data["isDefault"] = true
+			data = copyJSONObject(obj.data)
+		}
+		f.mu.Unlock()
+
+		if !ok {
+			writeFakeError(w, http.StatusNotFound, "Object "+id+" not found")
+			return
+		}
+		writeFakeJSON(w, http.StatusOK, data)
+	}
+}
+
+// storageSecrets returns the secrets of a file storage or BigQuery backend request, they are never returned.
+func storageSecrets(body map[string]interface{}) map[string]interface{} {
+	secrets := make(map[string]interface{})
+	for _, field := range []string{"awsSecret", "accountKey"} {
+		if v, ok := body[field]; ok {
+			secrets[field] = v
+		}
+	}
+	// GCS storages and BigQuery backends send the service account key
+	for _, field := range []string{"gcsCredentials", "credentials"} {
+		if v, ok := body[field].(map[string]interface{}); ok {
+			secrets["privateKey"] = v["private_key"]
+		}
+	}
+	return secrets
+}
+
+// fileStorageCreateResponse adapts a stored S3 or Azure Blob storage to the create response model,
+// which declares the ID as a string and omits the default flag.
+func fileStorageCreateResponse(data map[string]interface{}) map[string]interface{} {
+	data["id"] = stringValue(data["id"])
+	delete(data, "isDefault")
+	return data
+}
+
+func (f *fakeAPI) storageIndex(w http.ResponseWriter, _ *http.Request) {
+	writeFakeJSON(w, http.StatusOK, map[string]interface{}{
+		"services": []interface{}{map[string]interface{}{"id": "storage", "url": f.server.URL}},
+		"features": []interface{}{},
+	})
+}
+
+func (f *fakeAPI) listStorageTokens(w http.ResponseWriter, r *http.Request) {
+	current, _ := f.storageToken(r)
+	tokens := f.list(fakeTokens, current.parent)
+	for _, token := range tokens {
+		delete(token.(map[string]interface{}), "token")
+	}
+	writeFakeJSON(w, http.StatusOK, tokens)
+}
+
+func (f *fakeAPI) verifyStorageToken(w http.ResponseWriter, r *http.Request) {
+	obj, _ := f.storageToken(r)
+	writeFakeJSON(w, http.StatusOK, obj.data)
+}
+
+func (f *fakeAPI) storageTokenDetail(w http.ResponseWriter, r *http.Request) {
+	current, _ := f.storageToken(r)
+
+	f.mu.Lock()
+	obj, ok := f.objects[fakeTokens][r.PathValue("id")]
+	var data map[string]interface{}
+	if ok && obj.parent == current.parent {
+		data = copyJSONObject(obj.data)
+	}
+	f.mu.Unlock()
+
+	if data == nil {
+		writeFakeStorageError(w, http.StatusNotFound, "storage.tokens.notFound", "Token "+r.PathValue("id")+" not found")
+		return
+	}
+	// The token value is returned only on creation
+	delete(data, "token")
+	writeFakeJSON(w, http.StatusOK, data)
+}
+
+func (f *fakeAPI) updateStorageToken(w http.ResponseWriter, r *http.Request) {
+	current, _ := f.storageToken(r)
+	// Tokens are updated only by a token with the permission to manage tokens, not by themselves
+	if !boolValue(current.data["canManageTokens"]) {
+		writeFakeStorageError(w, http.StatusForbidden, "accessDenied", "You don't have access to manage tokens")
+		return
+	}
+	body, ok := decodeFakeBody(w, r)
+	if !ok {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[fakeTokens][r.PathValue("id")]
+	if !ok || obj.parent != current.parent {
+		writeFakeStorageError(w, http.StatusNotFound, "storage.tokens.notFound", "Token "+r.PathValue("id")+" not found")
+		return
+	}
+	for _, field := range []string{"description", "canManageBuckets", "canReadAllFileUploads", "canPurgeTrash", "bucketPermissions", "componentAccess"} {
+		if v, ok := body[field]; ok {
+			obj.data[field] = v
+		}
+	}
+	response := copyJSONObject(obj.data)
+	delete(response, "token")
+	writeFakeJSON(w, http.StatusOK, response)
+}
+
+func (f *fakeAPI) deleteStorageToken(w http.ResponseWriter, r *http.Request) {
+	current, _ := f.storageToken(r)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[fakeTokens][r.PathValue("id")]
+	if !ok || obj.parent != current.parent {
+		writeFakeStorageError(w, http.StatusNotFound, "storage.tokens.notFound", "Token "+r.PathValue("id")+" not found")
+		return
+	}
+	delete(f.objects[fakeTokens], r.PathValue("id"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// storageToken returns the stored token matching the X-StorageApi-Token header.
+func (f *fakeAPI) storageToken(r *http.Request) (*fakeObject, bool) {
+	value := r.Header.Get("X-StorageApi-Token")
+	if value == "" {
+		return nil, false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, obj := range f.objects[fakeTokens] {
+		if obj.data["token"] == value {
+			return &fakeObject{parent: obj.parent, data: copyJSONObject(obj.data)}, true
+		}
+	}
+	return nil, false
+}
+
+// listHandler returns all objects of the kind, optionally filtered by the parent from the path.
+// If fields are given, only these are returned, as required by strictly decoded SDK models.
+func (f *fakeAPI) listHandler(kind, parentKind string, fields ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		parentID := ""
+		if parentKind != "" {
+			parentID = r.PathValue("id")
+			if !f.exists(parentKind, parentID) {
+				writeFakeError(w, http.StatusNotFound, "Parent "+parentID+" not found")
+				return
+			}
+		}
+		items := f.list(kind, parentID)
+		if len(fields) > 0 {
+			for i, item := range items {
+				items[i] = pickFields(item.(map[string]interface{}), fields...)
+			}
+		}
+		writeFakeJSON(w, http.StatusOK, items)
+	}
+}
+
+func (f *fakeAPI) getHandler(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, ok := f.get(kind, r.PathValue("id"))
+		if !ok {
+			writeFakeError(w, http.StatusNotFound, "Object "+r.PathValue("id")+" not found")
+			return
+		}
+		writeFakeJSON(w, http.StatusOK, data)
+	}
+}
+
+// deleteHandler deletes an object, objects with children of childKind cannot be deleted.
+func (f *fakeAPI) deleteHandler(kind, childKind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if childKind != "" && len(f.list(childKind, id)) > 0 {
+			writeFakeError(w, http.StatusBadRequest, "Object "+id+" has existing "+childKind)
+			return
+		}
+		if !f.remove(kind, id) {
+			writeFakeError(w, http.StatusNotFound, "Object "+id+" not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// getChildHandler returns an object nested under a project.
+func (f *fakeAPI) getChildHandler(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		obj, ok := f.objects[kind][r.PathValue("invitation")]
+		var data map[string]interface{}
+		if ok && obj.parent == r.PathValue("id") {
+			data = copyJSONObject(obj.data)
+		}
+		f.mu.Unlock()
+
+		if data == nil {
+			writeFakeError(w, http.StatusNotFound, "Object "+r.PathValue("invitation")+" not found")
+			return
+		}
+		writeFakeJSON(w, http.StatusOK, data)
+	}
+}
+
+// deleteChildHandler deletes an object nested under a project.
+func (f *fakeAPI) deleteChildHandler(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		id := r.PathValue("invitation")
+		obj, ok := f.objects[kind][id]
+		if !ok || obj.parent != r.PathValue("id") {
+			writeFakeError(w, http.StatusNotFound, "Object "+id+" not found")
+			return
+		}
+		delete(f.objects[kind], id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// store saves a new object under a generated numeric ID and returns a copy of it.
+func (f *fakeAPI) store(kind, parent string, data map[string]interface{}) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := f.nextID(kind)
+	data["id"] = id
+	f.put(kind, strconv.Itoa(id), &fakeObject{parent: parent, data: data})
+	return copyJSONObject(data)
+}
+
+// nextID generates a numeric ID of a new object, the caller must hold the lock.
+// IDs have seven digits, so formatting them in the exponent notation of floats is detected.
+// Maintainer and organization IDs stay small, the SDK formats them as float32 path parameters.
+func (f *fakeAPI) nextID(kind string) int {
+	f.lastID++
+	if kind == fakeMaintainers || kind == fakeOrganizations {
+		return f.lastID
+	}
+	return 1000000 + f.lastID
+}
+
+// setSecrets replaces the write-only fields of a stored object.
+func (f *fakeAPI) setSecrets(kind, id string, secrets map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if obj, ok := f.objects[kind][id]; ok {
+		obj.secrets = secrets
+	}
+}
+
+// put stores the object, the caller must hold the lock.
+func (f *fakeAPI) put(kind, id string, obj *fakeObject) {
+	if f.objects[kind] == nil {
+		f.objects[kind] = make(map[string]*fakeObject)
+	}
+	f.objects[kind][id] = obj
+}
+
+// modify updates an existing object and writes it to the response, limited to fields if given.
+func (f *fakeAPI) modify(w http.ResponseWriter, kind, id string, fn func(obj *fakeObject), fields ...string) {
+	f.mu.Lock()
+	obj, ok := f.objects[kind][id]
+	var data map[string]interface{}
+	if ok {
+		fn(obj)
+		data = copyJSONObject(obj.data)
+		if len(fields) > 0 {
+			data = pickFields(data, fields...)
+		}
+	}
+	f.mu.Unlock()
+
+	if !ok {
+		writeFakeError(w, http.StatusNotFound, "Object "+id+" not found")
+		return
+	}
+	writeFakeJSON(w, http.StatusOK, data)
+}
+
+func (f *fakeAPI) exists(kind, id string) bool {
+	_, ok := f.get(kind, id)
+	return ok
+}
+
+// list returns copies of objects of the kind ordered by ID, an empty parent matches all objects.
+func (f *fakeAPI) list(kind, parent string) []interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]int, 0, len(f.objects[kind]))
+	for id, obj := range f.objects[kind] {
+		if parent == "" || obj.parent == parent {
+			n, _ := strconv.Atoi(id)
+			ids = append(ids, n)
+		}
+	}
+	sort.Ints(ids)
+	result := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, copyJSONObject(f.objects[kind][strconv.Itoa(id)].data))
+	}
+	return result
+}
+
+func decodeFakeBody(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	body := make(map[string]interface{})
+	if r.ContentLength == 0 {
+		return body, true
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeFakeError(w, http.StatusBadRequest, "Invalid JSON body: "+err.Error())
+		return nil, false
+	}
+	return body, true
+}
+
+func writeFakeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeFakeError(w http.ResponseWriter, status int, message string) {
+	writeFakeJSON(w, status, map[string]interface{}{"error": message, "code": status, "status": "error"})
+}
+
+func writeFakeStorageError(w http.ResponseWriter, status int, code, message string) {
+	writeFakeJSON(w, status, map[string]interface{}{"error": message, "code": code, "status": "error"})
+}
+
+func fakeTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// copyJSONObject returns a deep copy, so the stored state cannot be changed through a response.
+func copyJSONObject(data map[string]interface{}) map[string]interface{} {
+	encoded, _ := json.Marshal(data)
+	result := make(map[string]interface{})
+	_ = json.Unmarshal(encoded, &result)
+	return result
+}
+
+// pickFields returns a copy of the object with the given fields only.
+func pickFields(data map[string]interface{}, fields ...string) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if v, ok := data[field]; ok {
+			result[field] = v
+		}
+	}
+	return result
+}
+
+// withoutPrivateKey returns GCP credentials as returned by the API, the private key is never returned.
+func withoutPrivateKey(credentials map[string]interface{}) map[string]interface{} {
+	result := copyJSONObject(credentials)
+	delete(result, "private_key")
+	return result
+}
+
+// setNumberField stores a number sent either as a JSON number or as a numeric string.
+func setNumberField(data map[string]interface{}, field string, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		delete(data, field)
+	case string:
+		if v == "" {
+			delete(data, field)
+		} else if n, err := strconv.ParseFloat(v, 64); err == nil {
+			data[field] = n
+		}
+	default:
+		data[field] = v
+	}
+}
+
+func numberValue(s string) float64 {
+	n, _ := strconv.ParseFloat(s, 64)
+	return n
+}
+
+func stringValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		// Large numbers are formatted without an exponent, as IDs in the API paths
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// boolValue accepts booleans as well as the "1", "0", "true" and "false" strings used by the API.
+func boolValue(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	default:
+		return false
+	}
+}
+
+// checkDestroy verifies that no objects of the kinds are left in the fake API.
+func (f *fakeAPI) checkDestroy(kinds ...string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		for _, kind := range kinds {
+			if n := f.count(kind); n > 0 {
+				return fmt.Errorf("%d %s still exist in the fake API", n, kind)
+			}
+		}
+		return nil
+	}
+}
+
+// checkField verifies a field of an object stored in the fake API, id is read when the check runs.
+func (f *fakeAPI) checkField(kind string, id *string, field string, expected interface{}) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		data, ok := f.get(kind, *id)
+		if !ok {
+			return fmt.Errorf("%s %q not found in the fake API", kind, *id)
+		}
+		if actual := stringValue(data[field]); actual != stringValue(expected) {
+			return fmt.Errorf("%s %q: expected %s to be %v, got %s", kind, *id, field, expected, actual)
+		}
+		return nil
+	}
+}
+
+// checkSecret verifies a write-only field of the object in the fake API, an empty expected value checks it is not set.
+func (f *fakeAPI) checkSecret(kind string, id *string, field, expected string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		obj, ok := f.objects[kind][*id]
+		if !ok {
+			return fmt.Errorf("%s %q not found in the fake API", kind, *id)
+		}
+		if actual := stringValue(obj.secrets[field]); actual != expected {
+			return fmt.Errorf("%s %q: unexpected secret %s", kind, *id, field)
+		}
+		return nil
+	}
+}
+
+// testAccCaptureID stores the ID of the resource, so that later steps can modify the object in the fake API.
+func testAccCaptureID(name string, id *string) resource.TestCheckFunc {
+	return resource.TestCheckResourceAttrWith(name, "id", func(value string) error {
+		*id = value
+		return nil
+	})
+}
+
+// testAccCheckFileStorageCount verifies the number of registered file storages of the kind.
+func testAccCheckFileStorageCount(fake *fakeAPI, kind string, expected int) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		if n := fake.count(kind); n != expected {
+			return fmt.Errorf("expected %d %s in the fake API, got %d", expected, kind, n)
+		}
+		return nil
+	}
+}
+
+// TestFakeAPI checks that the SDK decodes the responses of the fake API, so the acceptance tests
+// exercise the same decoding paths as a real stack.
+func TestFakeAPI(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeAPI(t)
+	api := fake.client().API
+
+	_, _, err := api.TokenVerificationAPI.TokenVerification(ctx).Execute()
+	require.NoError(t, err)
+
+	maintainer, _, err := api.MaintainersAPI.CreateAMaintainer(ctx).CreateAMaintainerRequest(management.CreateAMaintainerRequest{
+		Name:                         "tf-test-maintainer",
+		DefaultConnectionSnowflakeId: management.PtrString("12"),
+	}).Execute()
+	require.NoError(t, err)
+	maintainerID := int32(maintainer.GetId())
+	assert.Equal(t, float32(12), maintainer.GetDefaultConnectionSnowflakeId())
+
+	organization, _, err := api.OrganizationsAPI.CreateAnOrganization(ctx, float32(maintainerID)).CreateAnOrganizationRequest(management.CreateAnOrganizationRequest{
+		Name: management.PtrString("tf-test-organization"),
+	}).Execute()
+	require.NoError(t, err)
+
+	updated, _, err := api.OrganizationsAPI.UpdateAnOrganization(ctx, organization.GetId()).UpdateAnOrganizationRequest(management.UpdateAnOrganizationRequest{
+		MfaRequired:             management.PtrString("true"),
+		ActivityCenterProjectId: management.PtrString("5"),
+	}).Execute()
+	require.NoError(t, err)
+	assert.True(t, updated.GetMfaRequired())
+	assert.Equal(t, float32(5), updated.GetActivityCenterProjectId())
+
+	project, _, err := api.ProjectsAPI.AddAProject(ctx, strconv.FormatInt(int64(organization.GetId()), 10)).AddAProjectRequest(management.AddAProjectRequest{
+		Name:                    "tf-test-project",
+		Type:                    "demo",
+		DataRetentionTimeInDays: management.PtrString("7"),
+	}).Execute()
+	require.NoError(t, err)
+	projectID := strconv.FormatInt(int64(project.GetId()), 10)
+	assert.Equal(t, float32(7), project.GetDataRetentionTimeInDays())
+
+	_, _, err = api.SUPERFeaturesAPI.AddAProjectFeature(ctx, projectID).AddAProjectFeatureRequest(management.AddAProjectFeatureRequest{Feature: "tf-test-feature"}).Execute()
+	require.NoError(t, err)
+	project, _, err = api.ProjectsAPI.ProjectDetail(ctx, projectID).Execute()
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"tf-test-feature"}, project.Features)
+	_, err = api.SUPERFeaturesAPI.RemoveAProjectFeature(ctx, projectID, "tf-test-feature").Execute()
+	require.NoError(t, err)
+
+	invitation, _, err := api.ProjectsAPI.InviteAUserToAProject(ctx, projectID).InviteAUserToAProjectRequest(management.InviteAUserToAProjectRequest{
+		Email: "tf-test-user@example.com",
+	}).Execute()
+	require.NoError(t, err)
+	invitations, _, err := api.ProjectsAPI.ListProjectInvitations(ctx, projectID).Execute()
+	require.NoError(t, err)
+	require.Len(t, invitations, 1)
+	invitationID := strconv.FormatInt(int64(invitation.GetId()), 10)
+	_, err = api.ProjectsAPI.CancelProjectInvitation(ctx, projectID, invitationID).Execute()
+	require.NoError(t, err)
+	_, _, err = api.ProjectsAPI.ProjectInvitationDetail(ctx, projectID, invitationID).Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+
+	token, _, err := api.ProjectsAPI.CreateStorageToken(ctx, projectID).CreateStorageTokenRequest(management.CreateStorageTokenRequest{
+		Description: "tf-test-token",
+	}).Execute()
+	require.NoError(t, err)
+	storageAPI, err := fake.client().NewStorageAPI(ctx, token.GetToken())
+	require.NoError(t, err)
+	_, err = storageAPI.VerifyTokenRequest(token.GetToken()).Send(ctx)
+	require.NoError(t, err)
+	_, err = storageAPI.DeleteTokenRequest(token.GetId()).Send(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, fake.count(fakeTokens))
+
+	// Objects with children cannot be deleted
+	_, err = api.MaintainersAPI.DeleteAMaintainer(ctx, maintainerID).Execute()
+	require.Error(t, err)
+	_, err = api.ProjectsAPI.DeleteAProject(ctx, projectID).Execute()
+	require.NoError(t, err)
+	_, err = api.OrganizationsAPI.DeleteAnOrganization(ctx, organization.GetId()).Execute()
+	require.NoError(t, err)
+	_, err = api.MaintainersAPI.DeleteAMaintainer(ctx, maintainerID).Execute()
+	require.NoError(t, err)
+
+	backend, _, err := api.SUPERStorageBackendsManagementAPI.CreateANewBackend(ctx).CreateANewBackendRequest(management.CreateANewBackendRequest{
+		Backend: "snowflake", Host: "host", Username: "user", Password: "secret", Region: "us-east-1", Owner: "keboola",
+	}).Execute()
+	require.NoError(t, err)
+	backendID := strconv.FormatInt(int64(backend.Id), 10)
+	detail, _, err := api.SUPERStorageBackendsManagementAPI.BackendDetail(ctx, backendID).Execute()
+	require.NoError(t, err)
+	require.Len(t, detail, 1)
+	assert.NotContains(t, detail[0], "password")
+	_, _, err = api.SUPERStorageBackendsManagementAPI.UpdateBackend(ctx, backendID).StorageBackendUpdate(management.StorageBackendUpdate{Username: management.PtrString("other")}).Execute()
+	require.NoError(t, err)
+
+	credentials := management.CreateNewGoogleCloudStorageRequestGcsCredentials{
+		Type: "service_account", ProjectId: "p", PrivateKeyId: "k", PrivateKey: "secret", ClientEmail: "e", ClientId: "c",
+		AuthUri: "a", TokenUri: "t", AuthProviderX509CertUrl: "ap", ClientX509CertUrl: "cx",
+	}
+	bigQuery, _, err := api.SUPERStorageBackendsManagementAPI.CreateANewBigQueryBackend(ctx).CreateANewBigQueryBackendRequest(management.CreateANewBigQueryBackendRequest{
+		Owner: "keboola", FolderId: "123", Region: "us-east1", Credentials: &credentials,
+	}).Execute()
+	require.NoError(t, err)
+	assert.Equal(t, float32(123), bigQuery.GetFolderId())
+
+	s3, _, err := api.SUPERFileStorageManagementAPI.CreateNewAWSS3Storage(ctx).CreateNewAWSS3StorageRequest(management.CreateNewAWSS3StorageRequest{
+		AwsKey: "key", AwsSecret: "secret", FilesBucket: "bucket", Region: "us-east-1", Owner: "keboola",
+	}).Execute()
+	require.NoError(t, err)
+	assert.NotEmpty(t, s3.Id)
+
+	abs, _, err := api.SUPERFileStorageManagementAPI.CreateNewAzureBlobStorage(ctx).CreateNewAzureBlobStorageRequest(management.CreateNewAzureBlobStorageRequest{
+		AccountName: "account", AccountKey: "secret", Owner: "keboola",
+	}).Execute()
+	require.NoError(t, err)
+	assert.NotEmpty(t, abs.GetId())
+
+	gcs, _, err := api.SUPERFileStorageManagementAPI.CreateNewGoogleCloudStorage(ctx).CreateNewGoogleCloudStorageRequest(management.CreateNewGoogleCloudStorageRequest{
+		FilesBucket: "bucket", Owner: "keboola", Region: "us-east1", GcsCredentials: &credentials,
+	}).Execute()
+	require.NoError(t, err)
+	assert.NotZero(t, gcs.GetId())
+
+	storages, _, err := api.SUPERFileStorageManagementAPI.ListGoogleCloudStorage(ctx).Execute()
+	require.NoError(t, err)
+	require.Len(t, storages, 1)
+	assert.NotContains(t, storages[0].(map[string]interface{})["gcsCredentials"], "private_key")
+}
+
+func TestFakeAPIRejectsInvalidToken(t *testing.T) {
+	fake := newFakeAPI(t)
+	client := fake.client()
+	client.API.GetConfig().DefaultHeader["X-KBC-ManageApiToken"] = "invalid"
+
+	_, _, err := client.API.TokenVerificationAPI.TokenVerification(context.Background()).Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
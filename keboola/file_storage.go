@@ -0,0 +1,83 @@
+package keboola
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// File storage providers in the Management API paths, e.g. /manage/file-storage-s3.
+const (
+	fileStorageS3        = "s3"
+	fileStorageAzureBlob = "abs"
+	fileStorageGCS       = "gcs"
+)
+
+// setDefaultFileStorage marks the file storage as the default for new projects.
+// Only S3 and Azure Blob storages can be marked. The set-default responses of the SDK declare the ID
+// and the default flag as strings, so the request is sent directly and the response is ignored.
+func (c *Client) setDefaultFileStorage(ctx context.Context, provider, id string) error {
+	return c.sendManageRequest(ctx, http.MethodPost, fileStoragePath(provider, id)+"/default", nil, nil)
+}
+
+// updateFileStorageDefault applies a change of the is_default attribute.
+// The API cannot unset the default, another storage must be marked as the default instead.
+func (c *Client) updateFileStorageDefault(ctx context.Context, provider, title, id string, plan, state types.Bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	switch {
+	case plan.ValueBool() && !state.ValueBool():
+		if err := c.setDefaultFileStorage(ctx, provider, id); err != nil {
+			diags.AddError(
+				fmt.Sprintf("Error setting default %s file storage", title),
+				fmt.Sprintf("Could not set %s file storage %s as default: %s", title, id, err.Error()),
+			)
+		}
+	case !plan.IsUnknown() && !plan.ValueBool() && state.ValueBool():
+		diags.AddError(
+			fmt.Sprintf("Error unsetting default %s file storage", title),
+			fmt.Sprintf("The %s file storage %s cannot be unset as default, mark another file storage as default instead.", title, id),
+		)
+	}
+	return diags
+}
+
+// denyFileStorageChanges fails the plan when one of the attributes of an existing storage changes.
+// The API can neither update the settings nor rotate the credentials of a storage, and it cannot delete
+// a storage either, so a replacement would leave the old storage registered with its credentials.
+// The secret version is only recorded when it is not in the state yet, e.g. after import.
+// Unknown values are checked again when the plan is applied.
+func denyFileStorageChanges(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, title string, secretVersion path.Path, attributes ...path.Path) {
+	// Nothing to compare on create and destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+	for _, p := range append(attributes, secretVersion) {
+		var planValue, stateValue attr.Value
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, p, &planValue)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, p, &stateValue)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if planValue.IsUnknown() || planValue.Equal(stateValue) || (p.Equal(secretVersion) && stateValue.IsNull()) {
+			continue
+		}
+		resp.Diagnostics.AddAttributeError(
+			p,
+			fmt.Sprintf("Cannot change %s of the %s file storage", p, title),
+			fmt.Sprintf("The Keboola API cannot update the settings or rotate the credentials of a %s file storage, "+
+				"and it cannot delete file storages, so replacing the storage would leave the old one registered with its credentials. "+
+				"Revert the change of %s, and register a new file storage resource with the new settings instead.", title, p),
+		)
+	}
+}
+
+func fileStoragePath(provider, id string) string {
+	return "/manage/file-storage-" + provider + "/" + url.PathEscape(id)
+}
@@ -0,0 +1,46 @@
+package keboola
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// importIDSeparator separates the parts of a composite import ID, e.g. "123:456".
+const importIDSeparator = ":"
+
+// splitImportID splits a composite import ID into the given number of non-empty parts.
+// The last part may contain the separator, so values such as feature names are kept intact.
+func splitImportID(id string, parts int) ([]string, bool) {
+	values := strings.SplitN(id, importIDSeparator, parts)
+	if len(values) != parts {
+		return nil, false
+	}
+	for _, value := range values {
+		if value == "" {
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+// importCompositeID imports a resource identified by several attributes joined by ":".
+// Each part of the import ID is stored to the attribute of the same position,
+// format describes the expected ID in the error message, e.g. "project_id:invitation_id".
+func importCompositeID(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse, format string, attributes ...string) {
+	values, ok := splitImportID(req.ID, len(attributes))
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected import identifier",
+			fmt.Sprintf("Expected import identifier with format: %s. Got: %q", format, req.ID),
+		)
+		return
+	}
+
+	for i, attribute := range attributes {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(attribute), values[i])...)
+	}
+}
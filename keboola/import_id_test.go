@@ -0,0 +1,40 @@
+package keboola
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitImportID(t *testing.T) {
+	values, ok := splitImportID("123:456", 2)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"123", "456"}, values)
+
+	values, ok = splitImportID("123:feature:with:colons", 2)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"123", "feature:with:colons"}, values)
+
+	for _, id := range []string{"", "123", "123:", ":456"} {
+		_, ok := splitImportID(id, 2)
+		assert.False(t, ok, id)
+	}
+}
+
+// testAccCompositeImportID returns the composite import ID of the resource built from the given attributes.
+func testAccCompositeImportID(name string, attributes ...string) func(*terraform.State) (string, error) {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found in state", name)
+		}
+		values := make([]string, 0, len(attributes))
+		for _, attribute := range attributes {
+			values = append(values, rs.Primary.Attributes[attribute])
+		}
+		return strings.Join(values, importIDSeparator), nil
+	}
+}
@@ -0,0 +1,86 @@
+package keboola
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// apiError is returned by sendManageRequest and sendStorageRequest for unsuccessful responses.
+type apiError struct {
+	StatusCode int
+	Status     string
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return strings.TrimSpace(e.Status + " " + e.Message)
+}
+
+// sendManageRequest sends a JSON request to the Management API, for fields and endpoints the SDK does not cover.
+// It uses the HTTP client and the manage token of the SDK client, the response is decoded into result if not nil.
+func (c *Client) sendManageRequest(ctx context.Context, method, path string, body, result interface{}) error {
+	return c.sendRequest(ctx, method, path, c.API.GetConfig().DefaultHeader, body, result)
+}
+
+// sendStorageRequest sends a JSON request to the Storage API of the stack, authorized by the given storage token.
+// It is used for the Storage API endpoints the SDK does not cover.
+func (c *Client) sendStorageRequest(ctx context.Context, token, method, path string, body, result interface{}) error {
+	return c.sendRequest(ctx, method, path, map[string]string{"X-StorageApi-Token": token}, body, result)
+}
+
+// sendRequest sends a JSON request with the given headers to the stack URL.
+func (c *Client) sendRequest(ctx context.Context, method, path string, headers map[string]string, body, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("could not encode the request: %w", err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.APIURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("could not read the response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		apiErr := &apiError{StatusCode: resp.StatusCode, Status: resp.Status}
+		var errBody struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(respBody, &errBody) == nil {
+			apiErr.Message = errBody.Error
+		}
+		return apiErr
+	}
+
+	if result != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, result); err != nil {
+			return fmt.Errorf("could not decode the response: %w", err)
+		}
+	}
+	return nil
+}
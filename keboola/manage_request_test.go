@@ -0,0 +1,41 @@
+package keboola
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendManageRequest(t *testing.T) {
+	fake := newFakeAPI(t)
+	client := newClient(fake.server.URL, fakeManageToken, fake.server.Client())
+	ctx := context.Background()
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	err := client.sendManageRequest(ctx, http.MethodPost, "/manage/file-storage-abs", map[string]interface{}{
+		"accountName": "tftest",
+		"accountKey":  "secret",
+		"owner":       "keboola",
+	}, &created)
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID, "expected the ID of the created storage")
+
+	require.NoError(t, client.setDefaultFileStorage(ctx, fileStorageAzureBlob, created.ID))
+	require.NoError(t, fake.checkField(fakeABSStorages, &created.ID, "isDefault", true)(nil))
+
+	// Errors carry the status and the message of the API
+	var apiErr *apiError
+	err = client.setDefaultFileStorage(ctx, fileStorageAzureBlob, "999999")
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "Object 999999 not found", apiErr.Message)
+
+	err = client.sendManageRequest(ctx, http.MethodPost, "/manage/file-storage-abs", map[string]interface{}{}, nil)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+}
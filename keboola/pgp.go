@@ -0,0 +1,138 @@
+package keboola
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// keybasePrefix marks a pgp_key which references the public key of a Keybase user.
+const keybasePrefix = "keybase:"
+
+// keybaseLookupURL is the Keybase user lookup endpoint, tests point it to a local server.
+var keybaseLookupURL = "https://keybase.io/_/api/1.0/user/lookup.json"
+
+// encryptWithPGPKey encrypts the value for the public key and returns the base64 encoded
+// binary message together with the fingerprint of the key.
+func encryptWithPGPKey(entity *openpgp.Entity, value string) (string, string, error) {
+	var encrypted bytes.Buffer
+	plaintext, err := openpgp.Encrypt(&encrypted, []*openpgp.Entity{entity}, nil, nil, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("could not encrypt with the PGP key: %w", err)
+	}
+	if _, err := plaintext.Write([]byte(value)); err != nil {
+		return "", "", fmt.Errorf("could not encrypt with the PGP key: %w", err)
+	}
+	if err := plaintext.Close(); err != nil {
+		return "", "", fmt.Errorf("could not encrypt with the PGP key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(encrypted.Bytes()), hex.EncodeToString(entity.PrimaryKey.Fingerprint), nil
+}
+
+// retrievePGPKey returns the public key of a pgp_key attribute,
+// an armored public key or a "keybase:username" reference.
+// Keybase keys are fetched by the HTTP client of the provider.
+func retrievePGPKey(ctx context.Context, httpClient *http.Client, pgpKey string) (*openpgp.Entity, error) {
+	armored := pgpKey
+	if username, ok := strings.CutPrefix(pgpKey, keybasePrefix); ok {
+		var err error
+		if armored, err = keybasePublicKey(ctx, httpClient, username); err != nil {
+			return nil, err
+		}
+	}
+	return parsePGPKey(armored)
+}
+
+// parsePGPKey parses an armored public key, it must contain exactly one key.
+func parsePGPKey(armored string) (*openpgp.Entity, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+	if err != nil {
+		return nil, fmt.Errorf("could not read the PGP public key: %w", err)
+	}
+	if len(entities) != 1 {
+		return nil, fmt.Errorf("expected one PGP public key, got %d", len(entities))
+	}
+	return entities[0], nil
+}
+
+// keybasePublicKey returns the armored primary public key of the Keybase user.
+func keybasePublicKey(ctx context.Context, httpClient *http.Client, username string) (string, error) {
+	if username == "" {
+		return "", errors.New(`the Keybase username is missing, expected "keybase:username"`)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, keybaseLookupURL+"?"+url.Values{
+		"usernames": {username},
+		"fields":    {"public_keys"},
+	}.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	client := &http.Client{Transport: httpClient.Transport, Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("could not fetch the PGP key of Keybase user %q: %w", username, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("could not fetch the PGP key of Keybase user %q: %s", username, resp.Status)
+	}
+
+	var lookup struct {
+		Them []struct {
+			PublicKeys struct {
+				Primary struct {
+					Bundle string `json:"bundle"`
+				} `json:"primary"`
+			} `json:"public_keys"`
+		} `json:"them"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&lookup); err != nil {
+		return "", fmt.Errorf("could not decode the Keybase response: %w", err)
+	}
+	if len(lookup.Them) != 1 || lookup.Them[0].PublicKeys.Primary.Bundle == "" {
+		return "", fmt.Errorf("no PGP key found for Keybase user %q", username)
+	}
+	return lookup.Them[0].PublicKeys.Primary.Bundle, nil
+}
+
+// pgpKeyValidator checks that a pgp_key is an armored public key or a Keybase reference.
+// Keybase keys are fetched only when they are used.
+type pgpKeyValidator struct{}
+
+var _ validator.String = pgpKeyValidator{}
+
+func (v pgpKeyValidator) Description(_ context.Context) string {
+	return `value must be an armored PGP public key or "keybase:username"`
+}
+
+func (v pgpKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v pgpKeyValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	value := req.ConfigValue.ValueString()
+	if username, ok := strings.CutPrefix(value, keybasePrefix); ok {
+		if username == "" {
+			resp.Diagnostics.AddAttributeError(req.Path, "Invalid PGP key", `The Keybase username is missing, expected "keybase:username".`)
+		}
+		return
+	}
+	if _, err := parsePGPKey(value); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid PGP key", "The value must be an armored PGP public key or \"keybase:username\": "+err.Error())
+	}
+}
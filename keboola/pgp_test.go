@@ -0,0 +1,85 @@
+package keboola
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptWithPGPKey(t *testing.T) {
+	entity, armored := testPGPKey(t)
+
+	key, err := retrievePGPKey(context.Background(), http.DefaultClient, armored)
+	require.NoError(t, err)
+	encrypted, fingerprint, err := encryptWithPGPKey(key, "secret-token")
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(entity.PrimaryKey.Fingerprint), fingerprint)
+	assert.Equal(t, "secret-token", testPGPDecrypt(t, entity, encrypted))
+
+	_, err = retrievePGPKey(context.Background(), http.DefaultClient, "not a key")
+	assert.ErrorContains(t, err, "could not read the PGP public key")
+}
+
+func TestRetrievePGPKey_keybase(t *testing.T) {
+	entity, armored := testPGPKey(t)
+	// Only the client of the TLS test server trusts its certificate, so the lookup must use the given client
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var them []interface{}
+		if r.URL.Query().Get("usernames") == "tf-test" {
+			them = append(them, map[string]interface{}{
+				"public_keys": map[string]interface{}{"primary": map[string]interface{}{"bundle": armored}},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"them": them})
+	}))
+	defer server.Close()
+	original := keybaseLookupURL
+	keybaseLookupURL = server.URL
+	defer func() { keybaseLookupURL = original }()
+
+	key, err := retrievePGPKey(context.Background(), server.Client(), "keybase:tf-test")
+	require.NoError(t, err)
+	assert.Equal(t, entity.PrimaryKey.Fingerprint, key.PrimaryKey.Fingerprint)
+
+	_, err = retrievePGPKey(context.Background(), server.Client(), "keybase:unknown")
+	assert.ErrorContains(t, err, `no PGP key found for Keybase user "unknown"`)
+	_, err = retrievePGPKey(context.Background(), server.Client(), "keybase:")
+	assert.ErrorContains(t, err, "the Keybase username is missing")
+}
+
+// testPGPKey generates a PGP key and returns it together with its armored public key.
+func testPGPKey(t *testing.T) (*openpgp.Entity, string) {
+	t.Helper()
+	entity, err := openpgp.NewEntity("tf-test", "", "tf-test@example.com", nil)
+	require.NoError(t, err)
+
+	var armored bytes.Buffer
+	w, err := armor.Encode(&armored, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+	return entity, armored.String()
+}
+
+// testPGPDecrypt decrypts a base64 encoded message with the private key.
+func testPGPDecrypt(t *testing.T, entity *openpgp.Entity, encrypted string) string {
+	t.Helper()
+	message, err := base64.StdEncoding.DecodeString(encrypted)
+	require.NoError(t, err)
+	details, err := openpgp.ReadMessage(bytes.NewReader(message), openpgp.EntityList{entity}, nil, nil)
+	require.NoError(t, err)
+	decrypted, err := io.ReadAll(details.UnverifiedBody)
+	require.NoError(t, err)
+	return string(decrypted)
+}
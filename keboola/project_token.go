@@ -0,0 +1,114 @@
+package keboola
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	sdk "github.com/keboola/keboola-sdk-go/v2/pkg/keboola"
+	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
+)
+
+// Permissions which can be granted to a storage token on a bucket.
+const (
+	bucketPermissionRead  = "read"
+	bucketPermissionWrite = "write"
+)
+
+// bucketIDRegexp matches a bucket ID, e.g. in.c-main.
+var bucketIDRegexp = regexp.MustCompile(`^(in|out|sys)\.[a-zA-Z0-9_-]+$`)
+
+// projectTokenBucketPermissionsValidators validates the bucket_permissions map of storage tokens.
+func projectTokenBucketPermissionsValidators() []validator.Map {
+	return []validator.Map{
+		mapvalidator.KeysAre(stringvalidator.RegexMatches(bucketIDRegexp, "must be a bucket ID, e.g. in.c-main")),
+		mapvalidator.ValueStringsAre(stringvalidator.OneOf(bucketPermissionRead, bucketPermissionWrite)),
+	}
+}
+
+// createProjectToken creates a storage token in the project.
+// The request is sent directly, because the SDK model drops the bucket permissions other than "in.c".
+func (c *Client) createProjectToken(ctx context.Context, projectID string, body map[string]interface{}) (*management.CreateStorageToken201Response, error) {
+	var result management.CreateStorageToken201Response
+	if err := c.sendManageRequest(ctx, http.MethodPost, fmt.Sprintf("/manage/projects/%s/tokens", url.PathEscape(projectID)), body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// projectTokenUpdaterExpiresIn is the lifetime in seconds of the token which authorizes a token update.
+const projectTokenUpdaterExpiresIn = 300
+
+// withProjectTokenManager calls fn with a short-lived token of the project with the permission to manage tokens,
+// which is deleted afterwards. It authorizes the Storage API requests on other tokens of the project,
+// e.g. when the token value is not known or a token cannot change its own permissions.
+func (c *Client) withProjectTokenManager(ctx context.Context, projectID, description string, fn func(token string) error) error {
+	canManageTokens := true
+	expiresIn := float32(projectTokenUpdaterExpiresIn)
+	manager, _, err := c.API.ProjectsAPI.CreateStorageToken(ctx, projectID).CreateStorageTokenRequest(management.CreateStorageTokenRequest{
+		Description:     description,
+		CanManageTokens: &canManageTokens,
+		ExpiresIn:       &expiresIn,
+	}).Execute()
+	if err != nil {
+		return fmt.Errorf("could not create a token to authorize the request: %w", err)
+	}
+
+	err = fn(manager.GetToken())
+
+	// The manager token expires on its own, so a failed deletion is only logged
+	if deleteErr := c.sendStorageRequest(ctx, manager.GetToken(), http.MethodDelete, "/v2/storage/tokens/"+url.PathEscape(manager.GetId()), nil, nil); deleteErr != nil {
+		tflog.Warn(ctx, fmt.Sprintf("Could not delete storage token %s used to authorize the request: %s", manager.GetId(), deleteErr.Error()))
+	}
+	return err
+}
+
+// updateProjectToken updates the settings of a storage token in place.
+// A token cannot change its own permissions, so the update is authorized by withProjectTokenManager.
+// The SDK does not cover the token update endpoint of the Storage API.
+// Differences of the granted permissions are detected by the next refresh.
+func (c *Client) updateProjectToken(ctx context.Context, projectID, tokenID string, body map[string]interface{}) error {
+	return c.withProjectTokenManager(ctx, projectID, "Terraform update of token "+tokenID, func(token string) error {
+		return c.sendStorageRequest(ctx, token, http.MethodPut, "/v2/storage/tokens/"+url.PathEscape(tokenID), body, nil)
+	})
+}
+
+// getProjectToken reads a storage token of the project by ID, for tokens whose value is not known,
+// e.g. after import or with pgp_key. A token which does not exist is returned as nil.
+func (c *Client) getProjectToken(ctx context.Context, projectID, tokenID string) (*sdk.Token, error) {
+	var result *sdk.Token
+	err := c.withProjectTokenManager(ctx, projectID, "Terraform refresh of token "+tokenID, func(token string) error {
+		var detail sdk.Token
+		err := c.sendStorageRequest(ctx, token, http.MethodGet, "/v2/storage/tokens/"+url.PathEscape(tokenID), nil, &detail)
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		result = &detail
+		return nil
+	})
+	return result, err
+}
+
+// deleteProjectToken deletes a storage token of the project by ID, for tokens whose value is not known,
+// e.g. after import or with pgp_key. A token which does not exist anymore is considered deleted.
+func (c *Client) deleteProjectToken(ctx context.Context, projectID, tokenID string) error {
+	return c.withProjectTokenManager(ctx, projectID, "Terraform deletion of token "+tokenID, func(token string) error {
+		err := c.sendStorageRequest(ctx, token, http.MethodDelete, "/v2/storage/tokens/"+url.PathEscape(tokenID), nil, nil)
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return err
+	})
+}
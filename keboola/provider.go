@@ -2,25 +2,35 @@ package keboola
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdkclient "github.com/keboola/keboola-sdk-go/v2/pkg/client"
+	sdk "github.com/keboola/keboola-sdk-go/v2/pkg/keboola"
 	keboola "github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
 )
 
 // Environment variable names for configuration
 const (
 	KbcHostnameSuffix = "KBC_HOSTNAME_SUFFIX"
+	KbcAPIURL         = "KBC_API_URL"
 	KbcToken          = "KBC_MANAGE_TOKEN"
+	KbcTokenFile      = "KBC_MANAGE_TOKEN_FILE"
 )
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ provider.Provider = &KeboolaProvider{}
+	_ provider.Provider                       = &KeboolaProvider{}
+	_ provider.ProviderWithEphemeralResources = &KeboolaProvider{}
 )
 
 // KeboolaProvider is the provider implementation.
@@ -34,12 +44,62 @@ func New() provider.Provider {
 // Client wraps the Keboola Management API client and exposes services.
 type Client struct {
 	API *keboola.APIClient
+	// APIURL is the base URL of the stack, e.g. https://connection.keboola.com
+	APIURL string
+	// HTTPClient carries the TLS, proxy and rate limit settings of the provider
+	HTTPClient *http.Client
+	// BaseHTTPClient carries the TLS and proxy settings without the rate limit,
+	// it is used for the calls outside the stack, e.g. the Keybase key lookup
+	BaseHTTPClient *http.Client
+}
+
+// newClient creates the Management API client authorized by the manage token.
+func newClient(apiURL, token string, httpClient *http.Client) *Client {
+	apiConfig := keboola.NewConfiguration()
+	apiConfig.Servers = keboola.ServerConfigurations{{URL: apiURL}}
+	apiConfig.AddDefaultHeader("X-KBC-ManageApiToken", token)
+	apiConfig.HTTPClient = httpClient
+
+	return &Client{
+		API:            keboola.NewAPIClient(apiConfig),
+		APIURL:         apiURL,
+		HTTPClient:     httpClient,
+		BaseHTTPClient: httpClient,
+	}
+}
+
+// stackAPIURL returns the base URL of the stack, apiURL if set, otherwise constructed from the hostname suffix.
+func stackAPIURL(apiURL, hostnameSuffix string) (string, error) {
+	if apiURL == "" {
+		apiURL = "https://connection." + hostnameSuffix
+	}
+	apiURL = strings.TrimRight(apiURL, "/")
+	if parsed, err := url.Parse(apiURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", errors.New("api_url must be an absolute http or https URL, e.g. https://connection.keboola.com, got: " + apiURL)
+	}
+	return apiURL, nil
+}
+
+// NewStorageAPI creates a Storage API client authorized by the given token.
+// The client shares the stack URL and HTTP settings of the provider.
+func (c *Client) NewStorageAPI(ctx context.Context, token string) (*sdk.AuthorizedAPI, error) {
+	httpClient := sdkclient.New().WithTransport(c.HTTPClient.Transport)
+	return sdk.NewAuthorizedAPI(ctx, c.APIURL, token, sdk.WithClient(&httpClient))
 }
 
 // KeboolaProviderModel describes the provider data model.
 type KeboolaProviderModel struct {
-	HostnameSuffix types.String `tfsdk:"hostname_suffix"`
-	Token          types.String `tfsdk:"token"`
+	HostnameSuffix        types.String  `tfsdk:"hostname_suffix"`
+	APIURL                types.String  `tfsdk:"api_url"`
+	Token                 types.String  `tfsdk:"token"`
+	TokenFile             types.String  `tfsdk:"token_file"`
+	TokenCommand          types.String  `tfsdk:"token_command"`
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	CACertFile            types.String  `tfsdk:"ca_cert_file"`
+	CACertPEM             types.String  `tfsdk:"ca_cert_pem"`
+	InsecureSkipVerify    types.Bool    `tfsdk:"insecure_skip_verify"`
+	ProxyURL              types.String  `tfsdk:"proxy_url"`
 }
 
 func (p *KeboolaProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -57,12 +117,57 @@ func (p *KeboolaProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 				MarkdownDescription: "The hostname suffix for the Keboola Domain e.g `keboola.com`. The provider will construct the full URL as `https://connection.{hostname_suffix}`. Can also be set via KBC_HOSTNAME_SUFFIX environment variable.",
 				Optional:            true,
 			},
+			"api_url": schema.StringAttribute{
+				Description:         "Full URL of the Keboola stack, e.g. https://connection.keboola.com. Overrides hostname_suffix. Can also be set via KBC_API_URL environment variable.",
+				MarkdownDescription: "Full URL of the Keboola stack, e.g. `https://connection.keboola.com` or `http://localhost:8080` for a local mock. Takes precedence over `hostname_suffix`. Can also be set via KBC_API_URL environment variable.",
+				Optional:            true,
+			},
 			"token": schema.StringAttribute{
 				Description:         "Keboola Management API Token. Can also be set via KBC_MANAGE_TOKEN environment variable.",
-				MarkdownDescription: "The Management API token used for authentication. This is a sensitive value and should be handled securely. Can also be set via KBC_MANAGE_TOKEN environment variable.",
+				MarkdownDescription: "The Management API token used for authentication. This is a sensitive value and should be handled securely. Can also be set via KBC_MANAGE_TOKEN environment variable. The token is resolved from the first source that is set: `token`, `token_file`, `token_command`, `KBC_MANAGE_TOKEN`, `KBC_MANAGE_TOKEN_FILE`.",
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"token_file": schema.StringAttribute{
+				Description:         "Path to a file containing the Management API token. Can also be set via KBC_MANAGE_TOKEN_FILE environment variable.",
+				MarkdownDescription: "Path to a file containing the Management API token, e.g. a mounted secret. Surrounding whitespace is trimmed. Can also be set via KBC_MANAGE_TOKEN_FILE environment variable.",
+				Optional:            true,
+			},
+			"token_command": schema.StringAttribute{
+				Description:         "Shell command printing the Management API token to standard output, e.g. a password manager CLI.",
+				MarkdownDescription: "Shell command printing the Management API token to standard output, e.g. `op read op://vault/keboola/token`. The output is trimmed and the command runs only once per provider run.",
+				Optional:            true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Description:         "Maximum number of Management API requests per second sent by this provider instance. Unlimited by default.",
+				MarkdownDescription: "Maximum number of Management API requests per second sent by this provider instance. The limit is shared by all resources, so large rollouts do not need `-parallelism=1`. Unlimited by default.",
+				Optional:            true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Description:         "Maximum number of Management API requests in flight at the same time for this provider instance. Unlimited by default.",
+				MarkdownDescription: "Maximum number of Management API requests in flight at the same time for this provider instance. The limit is shared by all resources. Unlimited by default.",
+				Optional:            true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description:         "Path to a PEM encoded CA bundle trusted in addition to the system roots. Conflicts with ca_cert_pem.",
+				MarkdownDescription: "Path to a PEM encoded CA bundle trusted in addition to the system roots, for stacks using a private CA. Conflicts with `ca_cert_pem`.",
+				Optional:            true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Description:         "PEM encoded CA bundle trusted in addition to the system roots. Conflicts with ca_cert_file.",
+				MarkdownDescription: "PEM encoded CA bundle trusted in addition to the system roots, for stacks using a private CA. Conflicts with `ca_cert_file`.",
+				Optional:            true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description:         "Skip TLS certificate verification. Intended for development only.",
+				MarkdownDescription: "Skip TLS certificate verification. Intended for development against local stacks only, never use it in production.",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				Description:         "URL of the HTTP proxy used for all API calls. Defaults to the HTTP_PROXY/HTTPS_PROXY environment variables.",
+				MarkdownDescription: "URL of the HTTP proxy used for all API calls, e.g. `http://proxy.example.com:3128`. Defaults to the `HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY` environment variables.",
+				Optional:            true,
+			},
 		},
 	}
 }
@@ -76,7 +181,7 @@ func (p *KeboolaProvider) Configure(ctx context.Context, req provider.ConfigureR
 	}
 
 	// Initialize variables to hold the final values
-	var hostnameSuffix, token string
+	var hostnameSuffix, apiURL, token string
 
 	// Check hostname suffix - use config if not unknown and not null, otherwise check environment
 	if config.HostnameSuffix.IsUnknown() || config.HostnameSuffix.IsNull() {
@@ -85,17 +190,39 @@ func (p *KeboolaProvider) Configure(ctx context.Context, req provider.ConfigureR
 		hostnameSuffix = config.HostnameSuffix.ValueString()
 	}
 
-	// Check token - use config if not unknown and not null, otherwise check environment
-	if config.Token.IsUnknown() || config.Token.IsNull() {
-		token = os.Getenv(KbcToken) //nolint: forbidigo
+	// Check API URL - use config if not unknown and not null, otherwise check environment
+	if config.APIURL.IsUnknown() || config.APIURL.IsNull() {
+		apiURL = os.Getenv(KbcAPIURL) //nolint: forbidigo
 	} else {
+		apiURL = config.APIURL.ValueString()
+	}
+
+	// Check token - the first source that is set wins, in this order:
+	// token, token_file, token_command, KBC_MANAGE_TOKEN and KBC_MANAGE_TOKEN_FILE
+	var err error
+	switch {
+	case !config.Token.IsUnknown() && !config.Token.IsNull():
 		token = config.Token.ValueString()
+	case !config.TokenFile.IsUnknown() && !config.TokenFile.IsNull():
+		token, err = readTokenFile(config.TokenFile.ValueString())
+	case !config.TokenCommand.IsUnknown() && !config.TokenCommand.IsNull():
+		token, err = runTokenCommand(ctx, config.TokenCommand.ValueString())
+	default:
+		token, err = tokenFromEnv()
 	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read token",
+			err.Error(),
+		)
+		return
+	}
+
 	// Validate that we have the required values
-	if hostnameSuffix == "" {
+	if apiURL == "" && hostnameSuffix == "" {
 		resp.Diagnostics.AddError(
 			"Unable to create client",
-			"Hostname suffix is required. Set it in the provider configuration or via KBC_HOSTNAME_SUFFIX environment variable.",
+			"Hostname suffix or API URL is required. Set it in the provider configuration or via KBC_HOSTNAME_SUFFIX or KBC_API_URL environment variable.",
 		)
 		return
 	}
@@ -103,24 +230,100 @@ func (p *KeboolaProvider) Configure(ctx context.Context, req provider.ConfigureR
 	if token == "" {
 		resp.Diagnostics.AddError(
 			"Unable to create client",
-			"Token is required. Set it in the provider configuration or via KBC_MANAGE_TOKEN environment variable.",
+			"Token is required. Set token, token_file or token_command in the provider configuration, or use KBC_MANAGE_TOKEN or KBC_MANAGE_TOKEN_FILE environment variable.",
 		)
 		return
 	}
 
-	// Construct the Management API URL from the hostname suffix
-	apiURL := "connection." + hostnameSuffix
+	var requestsPerSecond float64
+	if !config.RequestsPerSecond.IsUnknown() && !config.RequestsPerSecond.IsNull() {
+		requestsPerSecond = config.RequestsPerSecond.ValueFloat64()
+	}
+	if requestsPerSecond < 0 {
+		resp.Diagnostics.AddError(
+			"Invalid provider configuration",
+			"requests_per_second must not be negative.",
+		)
+		return
+	}
 
-	// Create a new configuration for the Management API client
-	apiConfig := keboola.NewConfiguration()
-	apiConfig.Host = apiURL
-	apiConfig.AddDefaultHeader("X-KBC-ManageApiToken", token)
+	var maxConcurrentRequests int64
+	if !config.MaxConcurrentRequests.IsUnknown() && !config.MaxConcurrentRequests.IsNull() {
+		maxConcurrentRequests = config.MaxConcurrentRequests.ValueInt64()
+	}
+	if maxConcurrentRequests < 0 {
+		resp.Diagnostics.AddError(
+			"Invalid provider configuration",
+			"max_concurrent_requests must not be negative.",
+		)
+		return
+	}
+
+	apiURL, err = stackAPIURL(apiURL, hostnameSuffix)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid provider configuration",
+			err.Error(),
+		)
+		return
+	}
+
+	transportConfig := transportConfig{
+		InsecureSkipVerify: config.InsecureSkipVerify.ValueBool(),
+	}
+	if !config.CACertFile.IsNull() && !config.CACertPEM.IsNull() {
+		resp.Diagnostics.AddError(
+			"Invalid provider configuration",
+			"Only one of ca_cert_file and ca_cert_pem can be set.",
+		)
+		return
+	}
+	if !config.CACertFile.IsNull() && config.CACertFile.ValueString() != "" {
+		pem, err := os.ReadFile(config.CACertFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid provider configuration",
+				"Could not read ca_cert_file: "+err.Error(),
+			)
+			return
+		}
+		transportConfig.CACertPEM = pem
+	}
+	if !config.CACertPEM.IsNull() && config.CACertPEM.ValueString() != "" {
+		transportConfig.CACertPEM = []byte(config.CACertPEM.ValueString())
+	}
+	if !config.ProxyURL.IsNull() && config.ProxyURL.ValueString() != "" {
+		proxyURL, err := url.Parse(config.ProxyURL.ValueString())
+		if err != nil || proxyURL.Host == "" {
+			resp.Diagnostics.AddError(
+				"Invalid provider configuration",
+				"proxy_url must be an absolute URL, e.g. http://proxy.example.com:3128, got: "+config.ProxyURL.ValueString(),
+			)
+			return
+		}
+		transportConfig.ProxyURL = proxyURL
+	}
+
+	baseTransport, err := newBaseTransport(transportConfig)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid provider configuration",
+			"Could not configure the HTTP transport: "+err.Error(),
+		)
+		return
+	}
+	httpClient := &http.Client{
+		// Rate limits are shared by all resources using this client, the logging sits
+		// below the limiter so that the logged duration covers only the API call
+		Transport: newRateLimitTransport(newLoggingTransport(baseTransport), requestsPerSecond, maxConcurrentRequests),
+	}
 
 	// Create the Management API client with the configured settings
-	apiClient := keboola.NewAPIClient(apiConfig)
+	client := newClient(apiURL, token, httpClient)
+	client.BaseHTTPClient = &http.Client{Transport: newLoggingTransport(baseTransport)}
 
 	// Verify the token
-	_, _, err := apiClient.TokenVerificationAPI.TokenVerification(ctx).Execute()
+	_, _, err = client.API.TokenVerificationAPI.TokenVerification(ctx).Execute()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to verify token",
@@ -129,23 +332,26 @@ func (p *KeboolaProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	client := &Client{
-		API: apiClient,
-	}
-
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.EphemeralResourceData = client
 }
 
 func (p *KeboolaProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewMaintainerResource,
+		NewManageTokenResource,          // Register the manage token resource
 		NewOrganizationResource,         // Register the organization resource
 		NewProjectResource,              // Register the project resource
 		NewProjectTokenResource,         // Register the project token resource
 		NewProjectInvitationResource,    // Register the project invitation resource
 		NewProjectFeatureResource,       // Register the project feature resource
 		NewBackendResource,              // Register the backend resource
+		NewBackendSnowflakeResource,     // Register the Snowflake backend resource
+		NewBackendRedshiftResource,      // Register the Redshift backend resource
+		NewBackendSynapseResource,       // Register the Synapse backend resource
+		NewBackendExasolResource,        // Register the Exasol backend resource
+		NewBackendTeradataResource,      // Register the Teradata backend resource
 		NewBackendBigQueryResource,      // Register the BigQuery backend resource
 		NewFileStorageS3Resource,        // Register the S3 file storage resource
 		NewFileStorageGCSResource,       // Register the GCS file storage resource
@@ -153,6 +359,12 @@ func (p *KeboolaProvider) Resources(_ context.Context) []func() resource.Resourc
 	}
 }
 
+func (p *KeboolaProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewProjectTokenEphemeralResource, // Register the ephemeral project token
+	}
+}
+
 func (p *KeboolaProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{}
 }
@@ -2,6 +2,7 @@ package keboola
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 
@@ -17,7 +18,7 @@ import (
 // CLI command executed to create a new provider server to which the CLI can
 // reattach.
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-	"keboola": providerserver.NewProtocol6WithError(New()),
+	"keboola-management": providerserver.NewProtocol6WithError(New()),
 }
 
 func TestProvider_impl(t *testing.T) {
@@ -54,37 +55,31 @@ func TestProvider(t *testing.T) {
 	})
 }
 
-func testAccPreCheck(t *testing.T) {
-	if v := os.Getenv("KEBOOLA_API_URL"); v == "" {
-		t.Fatal("KEBOOLA_API_URL must be set for acceptance tests")
-	}
-	if v := os.Getenv("KEBOOLA_TOKEN"); v == "" {
-		t.Fatal("KEBOOLA_TOKEN must be set for acceptance tests")
-	}
-}
-
 func TestAccProvider_basic(t *testing.T) {
+	fake := newFakeAPI(t)
+
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-		PreCheck:                 func() { testAccPreCheck(t) },
 		Steps: []resource.TestStep{
 			{
-				Config: testAccProviderConfig_basic,
+				Config: testAccProviderConfig_basic(fake.server.URL, fakeManageToken),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("keboola_maintainer.test", "name", "test"),
+					resource.TestCheckResourceAttr("keboola-management_maintainer.test", "name", "test"),
 				),
 			},
 		},
 	})
 }
 
-const testAccProviderConfig_basic = `
-provider "keboola" {
-  api_url = "https://connection.keboola.com"
-  token   = "your-token"
+func testAccProviderConfig_basic(apiURL, token string) string {
+	return fmt.Sprintf(`
+provider "keboola-management" {
+  api_url = %q
+  token   = %q
 }
 
-resource "keboola_maintainer" "test" {
+resource "keboola-management_maintainer" "test" {
   name = "test"
 }
-`
+`, apiURL, token)
+}
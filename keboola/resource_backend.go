@@ -3,19 +3,27 @@ package keboola
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
 )
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ resource.Resource              = &backendResource{}
-	_ resource.ResourceWithConfigure = &backendResource{}
+	_ resource.Resource                     = &backendResource{}
+	_ resource.ResourceWithConfigure        = &backendResource{}
+	_ resource.ResourceWithImportState      = &backendResource{}
+	_ resource.ResourceWithConfigValidators = &backendResource{}
+	_ resource.ResourceWithUpgradeState     = &backendResource{}
 )
 
 // NewBackendResource returns a new backend resource instance.
@@ -30,6 +38,23 @@ type backendResource struct {
 
 // backendResourceModel maps the resource schema data.
 type backendResourceModel struct {
+	ID                        types.String `tfsdk:"id"`
+	Backend                   types.String `tfsdk:"backend"`
+	Host                      types.String `tfsdk:"host"`
+	Username                  types.String `tfsdk:"username"`
+	Password                  types.String `tfsdk:"password"`
+	PasswordVersion           types.Int64  `tfsdk:"password_version"`
+	Region                    types.String `tfsdk:"region"`
+	Owner                     types.String `tfsdk:"owner"`
+	Warehouse                 types.String `tfsdk:"warehouse"`
+	Database                  types.String `tfsdk:"database"`
+	UseSynapseManagedIdentity types.Bool   `tfsdk:"use_synapse_managed_identity"`
+	UseDynamicBackends        types.Bool   `tfsdk:"use_dynamic_backends"`
+}
+
+// backendResourceModelV0 maps the schema version 0, which stored use_synapse_managed_identity as a string
+// and the password in the state.
+type backendResourceModelV0 struct {
 	ID                        types.String `tfsdk:"id"`
 	Backend                   types.String `tfsdk:"backend"`
 	Host                      types.String `tfsdk:"host"`
@@ -59,7 +84,8 @@ func (r *backendResource) Metadata(_ context.Context, req resource.MetadataReque
 // Schema defines the schema for the resource.
 func (r *backendResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manages a Keboola storage backend (except BigQuery).",
+		Description: "Manages a Keboola storage backend (except BigQuery). The engine-specific resources, e.g. keboola-management_backend_snowflake, validate the attributes of each engine in their schemas.",
+		Version:     1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "Backend ID.",
@@ -69,8 +95,11 @@ func (r *backendResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				},
 			},
 			"backend": schema.StringAttribute{
-				Description: "Backend type (e.g., bigquery, snowflake, redshift, synapse, exasol, teradata).",
+				Description: "Backend type: one of snowflake, redshift, synapse, exasol, teradata.",
 				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(backendEngineNames()...),
+				},
 			},
 			"host": schema.StringAttribute{
 				Description: "Backend host.",
@@ -81,9 +110,15 @@ func (r *backendResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Required:    true,
 			},
 			"password": schema.StringAttribute{
-				Description: "Password for backend.",
-				Required:    true,
-				Sensitive:   true,
+				Description: "Password for backend. The password is write-only and is not stored in the state, " +
+					"change password_version to send a new password.",
+				Required:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+			"password_version": schema.Int64Attribute{
+				Description: "Version of the password, a change sends the password to the API again.",
+				Optional:    true,
 			},
 			"region": schema.StringAttribute{
 				Description: "Backend region.",
@@ -94,30 +129,40 @@ func (r *backendResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Required:    true,
 			},
 			"warehouse": schema.StringAttribute{
-				Description: "Warehouse (required for Snowflake).",
+				Description: "Warehouse (required for Snowflake, not supported by other backends).",
 				Optional:    true,
 			},
 			"database": schema.StringAttribute{
-				Description: "Database (required for Synapse and Teradata).",
+				Description: "Database (required for Synapse and Teradata, not supported by other backends).",
 				Optional:    true,
 			},
-			"use_synapse_managed_identity": schema.StringAttribute{
-				Description: "Use Synapse Managed Identity (optional for Synapse).",
+			"use_synapse_managed_identity": schema.BoolAttribute{
+				Description: "Use Synapse Managed Identity (optional for Synapse, not supported by other backends).",
 				Optional:    true,
 			},
 			"use_dynamic_backends": schema.BoolAttribute{
-				Description: "Enable dynamic backends (only for supported backends, e.g., Snowflake).",
+				Description: "Enable dynamic backends (optional for Snowflake, not supported by other backends).",
 				Optional:    true,
 			},
 		},
 	}
 }
 
+// ConfigValidators enforces the attributes required and supported by each backend engine.
+func (r *backendResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		backendEngineConfigValidator{},
+	}
+}
+
 // Create creates the backend resource.
 func (r *backendResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan backendResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	// The password is write-only, it is available only in the configuration
+	var password types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -127,7 +172,7 @@ func (r *backendResource) Create(ctx context.Context, req resource.CreateRequest
 		Backend:  plan.Backend.ValueString(),
 		Host:     plan.Host.ValueString(),
 		Username: plan.Username.ValueString(),
-		Password: plan.Password.ValueString(),
+		Password: password.ValueString(),
 		Region:   plan.Region.ValueString(),
 		Owner:    plan.Owner.ValueString(),
 	}
@@ -139,10 +184,8 @@ func (r *backendResource) Create(ctx context.Context, req resource.CreateRequest
 		db := plan.Database.ValueString()
 		apiReq.Database = &db
 	}
-	if !plan.UseSynapseManagedIdentity.IsNull() && plan.UseSynapseManagedIdentity.ValueString() != "" {
-		id := plan.UseSynapseManagedIdentity.ValueString()
-		apiReq.UseSynapseManagedIdentity = &id
-	}
+	// The API expects the flag as a string
+	apiReq.UseSynapseManagedIdentity = formatBool(plan.UseSynapseManagedIdentity)
 	if !plan.UseDynamicBackends.IsNull() {
 		val := plan.UseDynamicBackends.ValueBool()
 		apiReq.UseDynamicBackends = &val
@@ -158,7 +201,7 @@ func (r *backendResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 	// apiResp.Id is float32, not a pointer
-	plan.ID = types.StringValue(fmt.Sprintf("%v", apiResp.Id))
+	plan.ID = types.StringValue(strconv.FormatInt(int64(apiResp.Id), 10))
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -174,15 +217,20 @@ func (r *backendResource) Read(ctx context.Context, req resource.ReadRequest, re
 	}
 
 	// Call the API to get backend details
-	apiResp, _, err := r.client.API.SUPERStorageBackendsManagementAPI.BackendDetail(ctx, state.ID.ValueString()).Execute()
+	apiResp, httpResp, err := r.client.API.SUPERStorageBackendsManagementAPI.BackendDetail(ctx, state.ID.ValueString()).Execute()
 	if err != nil {
+		// The backend was removed outside of Terraform
+		if httpResp != nil && httpResp.StatusCode == http.StatusNotFound {
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error reading backend",
 			fmt.Sprintf("Could not read backend '%s': %s", state.ID.ValueString(), err.Error()),
 		)
 		return
 	}
-	if apiResp == nil || len(apiResp) == 0 {
+	if len(apiResp) == 0 {
 		resp.State.RemoveResource(ctx)
 		return
 	}
@@ -196,7 +244,7 @@ func (r *backendResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 	if id, ok := backend["id"].(float64); ok {
-		state.ID = types.StringValue(fmt.Sprintf("%v", id))
+		state.ID = types.StringValue(strconv.FormatInt(int64(id), 10))
 	}
 	if backendType, ok := backend["backend"].(string); ok {
 		state.Backend = types.StringValue(backendType)
@@ -219,14 +267,13 @@ func (r *backendResource) Read(ctx context.Context, req resource.ReadRequest, re
 	if database, ok := backend["database"].(string); ok {
 		state.Database = types.StringValue(database)
 	}
-	if useSynapse, ok := backend["useSynapseManagedIdentity"].(string); ok {
-		state.UseSynapseManagedIdentity = types.StringValue(useSynapse)
+	if useSynapse, ok := backendDetailBool(backend, "useSynapseManagedIdentity"); ok {
+		state.UseSynapseManagedIdentity = useSynapse
 	}
 	if useDynamic, ok := backend["useDynamicBackends"].(bool); ok {
 		state.UseDynamicBackends = types.BoolValue(useDynamic)
 	}
-	// Password is not returned for security reasons
-	state.Password = types.StringNull()
+	// Password is write-only and is not returned for security reasons
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -234,9 +281,9 @@ func (r *backendResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 // Update updates the backend resource.
 func (r *backendResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan backendResourceModel
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+	var plan, state backendResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -247,9 +294,14 @@ func (r *backendResource) Update(ctx context.Context, req resource.UpdateRequest
 		u := plan.Username.ValueString()
 		apiReq.Username = &u
 	}
-	if !plan.Password.IsNull() && plan.Password.ValueString() != "" {
-		p := plan.Password.ValueString()
-		apiReq.Password = &p
+	// The write-only password is sent only when its version changes
+	if !plan.PasswordVersion.Equal(state.PasswordVersion) {
+		var password types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		apiReq.Password = password.ValueStringPointer()
 	}
 	if !plan.UseDynamicBackends.IsNull() {
 		val := plan.UseDynamicBackends.ValueBool()
@@ -266,7 +318,7 @@ func (r *backendResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	diags = resp.State.Set(ctx, plan)
+	diags := resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 }
 
@@ -289,3 +341,60 @@ func (r *backendResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 }
+
+// ImportState imports an existing backend by its ID.
+func (r *backendResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The password is write-only, it is sent again when password_version is changed
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// UpgradeState migrates the states stored by the previous schema versions.
+func (r *backendResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored use_synapse_managed_identity as a string
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":                           schema.StringAttribute{Computed: true},
+					"backend":                      schema.StringAttribute{Required: true},
+					"host":                         schema.StringAttribute{Required: true},
+					"username":                     schema.StringAttribute{Required: true},
+					"password":                     schema.StringAttribute{Required: true, Sensitive: true},
+					"region":                       schema.StringAttribute{Required: true},
+					"owner":                        schema.StringAttribute{Required: true},
+					"warehouse":                    schema.StringAttribute{Optional: true},
+					"database":                     schema.StringAttribute{Optional: true},
+					"use_synapse_managed_identity": schema.StringAttribute{Optional: true},
+					"use_dynamic_backends":         schema.BoolAttribute{Optional: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior backendResourceModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				state := backendResourceModel{
+					ID:       prior.ID,
+					Backend:  prior.Backend,
+					Host:     prior.Host,
+					Username: prior.Username,
+					// Version 0 stored the password, it is write-only since
+					Password:                  types.StringNull(),
+					PasswordVersion:           types.Int64Null(),
+					Region:                    prior.Region,
+					Owner:                     prior.Owner,
+					Warehouse:                 prior.Warehouse,
+					Database:                  prior.Database,
+					UseSynapseManagedIdentity: upgradeBool(prior.UseSynapseManagedIdentity, "use_synapse_managed_identity", &resp.Diagnostics),
+					UseDynamicBackends:        prior.UseDynamicBackends,
+				}
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+			},
+		},
+	}
+}
@@ -3,7 +3,10 @@ package keboola
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -12,12 +15,10 @@ import (
 	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
 )
 
-// This resource only supports Create and Update. Read and Delete are not supported by the API.
-// The resource will not be refreshed or deleted by Terraform. Document this clearly to users.
-
 var (
-	_ resource.Resource              = &backendBigQueryResource{}
-	_ resource.ResourceWithConfigure = &backendBigQueryResource{}
+	_ resource.Resource                = &backendBigQueryResource{}
+	_ resource.ResourceWithConfigure   = &backendBigQueryResource{}
+	_ resource.ResourceWithImportState = &backendBigQueryResource{}
 )
 
 // NewBackendBigQueryResource returns a new BigQuery backend resource instance.
@@ -35,6 +36,7 @@ type backendBigQueryCredentialsModel struct {
 	ProjectID               types.String `tfsdk:"project_id"`
 	PrivateKeyID            types.String `tfsdk:"private_key_id"`
 	PrivateKey              types.String `tfsdk:"private_key"`
+	PrivateKeyVersion       types.Int64  `tfsdk:"private_key_version"`
 	ClientEmail             types.String `tfsdk:"client_email"`
 	ClientID                types.String `tfsdk:"client_id"`
 	AuthURI                 types.String `tfsdk:"auth_uri"`
@@ -64,7 +66,7 @@ func (r *backendBigQueryResource) Metadata(_ context.Context, req resource.Metad
 
 func (r *backendBigQueryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Registers and updates a BigQuery backend. Only Create and Update are supported. Read and Delete are not supported by the API.",
+		Description: "Registers and updates a BigQuery backend.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "Backend ID.",
@@ -76,14 +78,23 @@ func (r *backendBigQueryResource) Schema(_ context.Context, _ resource.SchemaReq
 			"owner": schema.StringAttribute{
 				Description: "GCP account owner.",
 				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(), // The API updates only credentials
+				},
 			},
 			"folder_id": schema.StringAttribute{
 				Description: "GCP folder ID where the service account is created.",
 				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"region": schema.StringAttribute{
 				Description: "BigQuery region.",
 				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 		},
 		Blocks: map[string]schema.Block{
@@ -103,9 +114,15 @@ func (r *backendBigQueryResource) Schema(_ context.Context, _ resource.SchemaReq
 						Required:    true,
 					},
 					"private_key": schema.StringAttribute{
-						Description: "Private key.",
-						Required:    true,
-						Sensitive:   true,
+						Description: "Private key. The key is write-only and is not stored in the state, " +
+							"change private_key_version to send a new key.",
+						Required:  true,
+						Sensitive: true,
+						WriteOnly: true,
+					},
+					"private_key_version": schema.Int64Attribute{
+						Description: "Version of the private key, a change sends the credentials to the API again.",
+						Optional:    true,
 					},
 					"client_email": schema.StringAttribute{
 						Description: "Client email.",
@@ -139,26 +156,28 @@ func (r *backendBigQueryResource) Schema(_ context.Context, _ resource.SchemaReq
 
 // Create registers a new BigQuery backend.
 func (r *backendBigQueryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan backendBigQueryResourceModel
+	var plan, config backendBigQueryResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	// The private key is write-only, the credentials are taken from the configuration
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	var creds *management.CreateNewGoogleCloudStorageRequestGcsCredentials
-	if plan.Credentials != nil {
+	if config.Credentials != nil {
 		creds = &management.CreateNewGoogleCloudStorageRequestGcsCredentials{
-			Type:                    plan.Credentials.Type.ValueString(),
-			ProjectId:               plan.Credentials.ProjectID.ValueString(),
-			PrivateKeyId:            plan.Credentials.PrivateKeyID.ValueString(),
-			PrivateKey:              plan.Credentials.PrivateKey.ValueString(),
-			ClientEmail:             plan.Credentials.ClientEmail.ValueString(),
-			ClientId:                plan.Credentials.ClientID.ValueString(),
-			AuthUri:                 plan.Credentials.AuthURI.ValueString(),
-			TokenUri:                plan.Credentials.TokenURI.ValueString(),
-			AuthProviderX509CertUrl: plan.Credentials.AuthProviderX509CertURL.ValueString(),
-			ClientX509CertUrl:       plan.Credentials.ClientX509CertURL.ValueString(),
+			Type:                    config.Credentials.Type.ValueString(),
+			ProjectId:               config.Credentials.ProjectID.ValueString(),
+			PrivateKeyId:            config.Credentials.PrivateKeyID.ValueString(),
+			PrivateKey:              config.Credentials.PrivateKey.ValueString(),
+			ClientEmail:             config.Credentials.ClientEmail.ValueString(),
+			ClientId:                config.Credentials.ClientID.ValueString(),
+			AuthUri:                 config.Credentials.AuthURI.ValueString(),
+			TokenUri:                config.Credentials.TokenURI.ValueString(),
+			AuthProviderX509CertUrl: config.Credentials.AuthProviderX509CertURL.ValueString(),
+			ClientX509CertUrl:       config.Credentials.ClientX509CertURL.ValueString(),
 		}
 	}
 
@@ -177,39 +196,125 @@ func (r *backendBigQueryResource) Create(ctx context.Context, req resource.Creat
 		)
 		return
 	}
-	plan.ID = types.StringValue(fmt.Sprintf("%v", apiResp.Id))
+	plan.ID = types.StringValue(strconv.FormatInt(int64(apiResp.GetId()), 10))
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 }
 
-// Read is not supported by the API. Warn and do nothing.
+// Read refreshes the BigQuery backend state.
+// The private key is write-only and is not returned by the API, private_key_version is kept from state.
 func (r *backendBigQueryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	resp.Diagnostics.AddWarning("Read not supported", "BigQuery backend does not support read operation. State will not be refreshed.")
+	var state backendBigQueryResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Call the API to get backend details
+	apiResp, httpResp, err := r.client.API.SUPERStorageBackendsManagementAPI.BackendDetail(ctx, state.ID.ValueString()).Execute()
+	if err != nil {
+		// The backend was removed outside of Terraform
+		if httpResp != nil && httpResp.StatusCode == http.StatusNotFound {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading BigQuery backend",
+			fmt.Sprintf("Could not read BigQuery backend '%s': %s", state.ID.ValueString(), err.Error()),
+		)
+		return
+	}
+	if len(apiResp) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	// The response is []interface{}, the first element is the backend
+	backend, ok := apiResp[0].(map[string]interface{})
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Error parsing BigQuery backend detail",
+			"API response format unexpected.",
+		)
+		return
+	}
+	if owner, ok := backend["owner"].(string); ok {
+		state.Owner = types.StringValue(owner)
+	}
+	if region, ok := backend["region"].(string); ok {
+		state.Region = types.StringValue(region)
+	}
+	// The folder ID may be returned as a number
+	switch folderID := backend["folderId"].(type) {
+	case string:
+		state.FolderId = types.StringValue(folderID)
+	case float64:
+		state.FolderId = types.StringValue(strconv.FormatFloat(folderID, 'f', -1, 64))
+	}
+	if creds, ok := backend["credentials"].(map[string]interface{}); ok {
+		credModel := &backendBigQueryCredentialsModel{}
+		if state.Credentials != nil {
+			*credModel = *state.Credentials
+		}
+		if v, ok := creds["type"].(string); ok {
+			credModel.Type = types.StringValue(v)
+		}
+		if v, ok := creds["project_id"].(string); ok {
+			credModel.ProjectID = types.StringValue(v)
+		}
+		if v, ok := creds["private_key_id"].(string); ok {
+			credModel.PrivateKeyID = types.StringValue(v)
+		}
+		if v, ok := creds["client_email"].(string); ok {
+			credModel.ClientEmail = types.StringValue(v)
+		}
+		if v, ok := creds["client_id"].(string); ok {
+			credModel.ClientID = types.StringValue(v)
+		}
+		if v, ok := creds["auth_uri"].(string); ok {
+			credModel.AuthURI = types.StringValue(v)
+		}
+		if v, ok := creds["token_uri"].(string); ok {
+			credModel.TokenURI = types.StringValue(v)
+		}
+		if v, ok := creds["auth_provider_x509_cert_url"].(string); ok {
+			credModel.AuthProviderX509CertURL = types.StringValue(v)
+		}
+		if v, ok := creds["client_x509_cert_url"].(string); ok {
+			credModel.ClientX509CertURL = types.StringValue(v)
+		}
+		state.Credentials = credModel
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 }
 
 // Update updates the BigQuery backend.
 func (r *backendBigQueryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan backendBigQueryResourceModel
+	var plan, config backendBigQueryResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	// The private key is write-only, the credentials are taken from the configuration
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	var creds *management.GCPCredentials
-	if plan.Credentials != nil {
+	if config.Credentials != nil {
 		creds = &management.GCPCredentials{
-			Type:                    plan.Credentials.Type.ValueString(),
-			ProjectId:               plan.Credentials.ProjectID.ValueString(),
-			PrivateKeyId:            plan.Credentials.PrivateKeyID.ValueString(),
-			PrivateKey:              plan.Credentials.PrivateKey.ValueString(),
-			ClientEmail:             plan.Credentials.ClientEmail.ValueString(),
-			ClientId:                plan.Credentials.ClientID.ValueString(),
-			AuthUri:                 plan.Credentials.AuthURI.ValueString(),
-			TokenUri:                plan.Credentials.TokenURI.ValueString(),
-			AuthProviderX509CertUrl: plan.Credentials.AuthProviderX509CertURL.ValueString(),
-			ClientX509CertUrl:       plan.Credentials.ClientX509CertURL.ValueString(),
+			Type:                    config.Credentials.Type.ValueString(),
+			ProjectId:               config.Credentials.ProjectID.ValueString(),
+			PrivateKeyId:            config.Credentials.PrivateKeyID.ValueString(),
+			PrivateKey:              config.Credentials.PrivateKey.ValueString(),
+			ClientEmail:             config.Credentials.ClientEmail.ValueString(),
+			ClientId:                config.Credentials.ClientID.ValueString(),
+			AuthUri:                 config.Credentials.AuthURI.ValueString(),
+			TokenUri:                config.Credentials.TokenURI.ValueString(),
+			AuthProviderX509CertUrl: config.Credentials.AuthProviderX509CertURL.ValueString(),
+			ClientX509CertUrl:       config.Credentials.ClientX509CertURL.ValueString(),
 		}
 	}
 
@@ -230,7 +335,32 @@ func (r *backendBigQueryResource) Update(ctx context.Context, req resource.Updat
 	resp.Diagnostics.Append(diags...)
 }
 
-// Delete is not supported by the API. Warn and do nothing.
+// Delete removes the BigQuery backend.
 func (r *backendBigQueryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	resp.Diagnostics.AddWarning("Delete not supported", "BigQuery backend does not support delete operation. Resource will remain in state.")
+	var state backendBigQueryResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// BigQuery backends are removed by the same endpoint as other backends
+	httpResp, err := r.client.API.SUPERStorageBackendsManagementAPI.DeleteBackend(ctx, state.ID.ValueString()).Execute()
+	if err != nil {
+		// The backend is already gone
+		if httpResp != nil && httpResp.StatusCode == http.StatusNotFound {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error deleting BigQuery backend",
+			fmt.Sprintf("Could not delete BigQuery backend '%s': %s", state.ID.ValueString(), err.Error()),
+		)
+		return
+	}
+}
+
+// ImportState imports an existing BigQuery backend by its ID.
+func (r *backendBigQueryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The private key is write-only, it is sent again when private_key_version is changed
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
@@ -0,0 +1,410 @@
+package keboola
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
+)
+
+// backendEngineRule lists the engine-specific attributes of a storage backend.
+type backendEngineRule struct {
+	// required attributes must be set for the engine
+	required []string
+	// optional attributes may be set for the engine
+	optional []string
+}
+
+// backendEngines are the engines registered by CreateANewBackend. BigQuery has its own endpoint and resource.
+// The rules are enforced by the schemas of the typed resources and by the config validator of the generic resource.
+var backendEngines = map[string]backendEngineRule{
+	"snowflake": {required: []string{"warehouse"}, optional: []string{"use_dynamic_backends"}},
+	"redshift":  {},
+	"synapse":   {required: []string{"database"}, optional: []string{"use_synapse_managed_identity"}},
+	"exasol":    {},
+	"teradata":  {required: []string{"database"}},
+}
+
+// backendEngineAttributes are the attributes of the generic backend resource which depend on the engine.
+var backendEngineAttributes = []string{"warehouse", "database", "use_synapse_managed_identity", "use_dynamic_backends"}
+
+// backendEngineNames returns the engine names in a stable order, for validators and messages.
+func backendEngineNames() []string {
+	return []string{"snowflake", "redshift", "synapse", "exasol", "teradata"}
+}
+
+// backendEngineCommonModel maps the attributes shared by all typed backend resources.
+type backendEngineCommonModel struct {
+	ID              types.String `tfsdk:"id"`
+	Host            types.String `tfsdk:"host"`
+	Username        types.String `tfsdk:"username"`
+	Password        types.String `tfsdk:"password"`
+	PasswordVersion types.Int64  `tfsdk:"password_version"`
+	Region          types.String `tfsdk:"region"`
+	Owner           types.String `tfsdk:"owner"`
+}
+
+// backendEngineModel is implemented by the models of the typed backend resources.
+type backendEngineModel interface {
+	// common returns the attributes shared by all engines
+	common() *backendEngineCommonModel
+	// setCreateRequest sets the engine-specific fields of the create request
+	setCreateRequest(req *management.CreateANewBackendRequest)
+	// setUpdateRequest sets the engine-specific fields of the update request
+	setUpdateRequest(req *management.StorageBackendUpdate)
+	// setDetail refreshes the engine-specific attributes from the backend detail
+	setDetail(backend map[string]interface{})
+}
+
+// backendEngineResource implements a storage backend resource of a single engine.
+// M is the model of the engine, PM is its pointer type implementing backendEngineModel.
+type backendEngineResource[M any, PM interface {
+	*M
+	backendEngineModel
+}] struct {
+	client *Client
+	// engine is the backend type sent to the API, e.g. snowflake
+	engine string
+	// title is the engine name used in descriptions and messages, e.g. Snowflake
+	title string
+	// attributes are the engine-specific schema attributes
+	attributes map[string]schema.Attribute
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *backendEngineResource[M, PM]) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.client = req.ProviderData.(*Client)
+}
+
+// Metadata returns the resource type name.
+func (r *backendEngineResource[M, PM]) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_backend_" + r.engine
+}
+
+// Schema defines the schema for the resource.
+func (r *backendEngineResource[M, PM]) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	notEmpty := []validator.String{stringvalidator.LengthAtLeast(1)}
+	attributes := map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: "Backend ID.",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"host": schema.StringAttribute{
+			Description: fmt.Sprintf("%s host.", r.title),
+			Required:    true,
+			Validators:  notEmpty,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(), // The API updates only credentials
+			},
+		},
+		"username": schema.StringAttribute{
+			Description: fmt.Sprintf("Username for %s.", r.title),
+			Required:    true,
+			Validators:  notEmpty,
+		},
+		"password": schema.StringAttribute{
+			Description: fmt.Sprintf("Password for %s. The password is write-only and is not stored in the state, ", r.title) +
+				"change password_version to send a new password.",
+			Required:   true,
+			Sensitive:  true,
+			WriteOnly:  true,
+			Validators: notEmpty,
+		},
+		"password_version": schema.Int64Attribute{
+			Description: "Version of the password, a change sends the password to the API again.",
+			Optional:    true,
+		},
+		"region": schema.StringAttribute{
+			Description: "Backend region.",
+			Required:    true,
+			Validators:  notEmpty,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"owner": schema.StringAttribute{
+			Description: "Associated AWS account owner.",
+			Required:    true,
+			Validators:  notEmpty,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+	}
+	for name, attribute := range r.attributes {
+		attributes[name] = attribute
+	}
+
+	resp.Schema = schema.Schema{
+		Description: fmt.Sprintf("Manages a Keboola %s storage backend.", r.title),
+		Attributes:  attributes,
+	}
+}
+
+// Create registers the backend.
+func (r *backendEngineResource[M, PM]) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan M
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	// The password is write-only, it is available only in the configuration
+	var password types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model := PM(&plan)
+	common := model.common()
+
+	// Build the API request
+	apiReq := management.CreateANewBackendRequest{
+		Backend:  r.engine,
+		Host:     common.Host.ValueString(),
+		Username: common.Username.ValueString(),
+		Password: password.ValueString(),
+		Region:   common.Region.ValueString(),
+		Owner:    common.Owner.ValueString(),
+	}
+	model.setCreateRequest(&apiReq)
+
+	apiResp, _, err := r.client.API.SUPERStorageBackendsManagementAPI.CreateANewBackend(ctx).CreateANewBackendRequest(apiReq).Execute()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Error creating %s backend", r.title),
+			fmt.Sprintf("Could not create %s backend: %s", r.title, err.Error()),
+		)
+		return
+	}
+	common.ID = types.StringValue(strconv.FormatInt(int64(apiResp.Id), 10))
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the backend state. The password is write-only and is not returned by the API.
+func (r *backendEngineResource[M, PM]) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state M
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model := PM(&state)
+	common := model.common()
+
+	apiResp, httpResp, err := r.client.API.SUPERStorageBackendsManagementAPI.BackendDetail(ctx, common.ID.ValueString()).Execute()
+	if err != nil {
+		// The backend was removed outside of Terraform
+		if httpResp != nil && httpResp.StatusCode == http.StatusNotFound {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Error reading %s backend", r.title),
+			fmt.Sprintf("Could not read backend '%s': %s", common.ID.ValueString(), err.Error()),
+		)
+		return
+	}
+	if len(apiResp) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	// The response is []interface{}, the first element is the backend
+	backend, ok := apiResp[0].(map[string]interface{})
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Error parsing backend detail",
+			"API response format unexpected.",
+		)
+		return
+	}
+	if backendType, ok := backend["backend"].(string); ok && backendType != r.engine {
+		resp.Diagnostics.AddError(
+			"Unexpected backend type",
+			fmt.Sprintf("Backend '%s' is a %s backend, use the keboola-management_backend_%s or keboola-management_backend resource.", common.ID.ValueString(), backendType, backendType),
+		)
+		return
+	}
+	if host, ok := backend["host"].(string); ok {
+		common.Host = types.StringValue(host)
+	}
+	if username, ok := backend["username"].(string); ok {
+		common.Username = types.StringValue(username)
+	}
+	if region, ok := backend["region"].(string); ok {
+		common.Region = types.StringValue(region)
+	}
+	if owner, ok := backend["owner"].(string); ok {
+		common.Owner = types.StringValue(owner)
+	}
+	model.setDetail(backend)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the backend credentials and settings supported by UpdateBackend.
+func (r *backendEngineResource[M, PM]) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state M
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model := PM(&plan)
+	common := model.common()
+
+	username := common.Username.ValueString()
+	apiReq := management.StorageBackendUpdate{
+		Username: &username,
+	}
+	// The write-only password is sent only when its version changes
+	if !common.PasswordVersion.Equal(PM(&state).common().PasswordVersion) {
+		var password types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		apiReq.Password = password.ValueStringPointer()
+	}
+	model.setUpdateRequest(&apiReq)
+
+	_, _, err := r.client.API.SUPERStorageBackendsManagementAPI.UpdateBackend(ctx, common.ID.ValueString()).StorageBackendUpdate(apiReq).Execute()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Error updating %s backend", r.title),
+			fmt.Sprintf("Could not update backend '%s': %s", common.ID.ValueString(), err.Error()),
+		)
+		return
+	}
+
+	diags := resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete removes the backend.
+func (r *backendEngineResource[M, PM]) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state M
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	id := PM(&state).common().ID.ValueString()
+
+	httpResp, err := r.client.API.SUPERStorageBackendsManagementAPI.DeleteBackend(ctx, id).Execute()
+	if err != nil {
+		// The backend is already gone
+		if httpResp != nil && httpResp.StatusCode == http.StatusNotFound {
+			return
+		}
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Error deleting %s backend", r.title),
+			fmt.Sprintf("Could not delete backend '%s': %s", id, err.Error()),
+		)
+		return
+	}
+}
+
+// ImportState imports an existing backend by its ID.
+func (r *backendEngineResource[M, PM]) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The password is write-only, it is sent again when password_version is changed
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// backendDetailString returns a string field of the backend detail as a Terraform value, null if it is missing.
+func backendDetailString(backend map[string]interface{}, field string) (types.String, bool) {
+	v, ok := backend[field].(string)
+	if !ok {
+		return types.StringNull(), false
+	}
+	return types.StringValue(v), true
+}
+
+// backendDetailBool returns a flag of the backend detail, the API may return it as a bool or a string.
+func backendDetailBool(backend map[string]interface{}, field string) (types.Bool, bool) {
+	switch v := backend[field].(type) {
+	case bool:
+		return types.BoolValue(v), true
+	case string:
+		if parsed, err := strconv.ParseBool(v); err == nil {
+			return types.BoolValue(parsed), true
+		}
+	}
+	return types.BoolNull(), false
+}
+
+// backendEngineConfigValidator enforces the engine-specific attributes of the generic backend resource.
+type backendEngineConfigValidator struct{}
+
+var _ resource.ConfigValidator = backendEngineConfigValidator{}
+
+func (v backendEngineConfigValidator) Description(_ context.Context) string {
+	return "Checks that the attributes required by the backend engine are set and that attributes of other engines are not."
+}
+
+func (v backendEngineConfigValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v backendEngineConfigValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var backend types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("backend"), &backend)...)
+	if resp.Diagnostics.HasError() || backend.IsNull() || backend.IsUnknown() {
+		return
+	}
+	rule, ok := backendEngines[backend.ValueString()]
+	if !ok {
+		// Unknown engines are reported by the validator of the backend attribute
+		return
+	}
+
+	supported := map[string]bool{}
+	for _, name := range rule.required {
+		supported[name] = true
+	}
+	for _, name := range rule.optional {
+		supported[name] = true
+	}
+
+	for _, name := range backendEngineAttributes {
+		var value attr.Value
+		diags := req.Config.GetAttribute(ctx, path.Root(name), &value)
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			return
+		}
+		isSet := !value.IsNull()
+		switch {
+		case !isSet && slices.Contains(rule.required, name):
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Missing required attribute",
+				fmt.Sprintf("The %q attribute is required for the %s backend.", name, backend.ValueString()),
+			)
+		case isSet && !supported[name]:
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Unsupported attribute",
+				fmt.Sprintf("The %q attribute is not supported for the %s backend.", name, backend.ValueString()),
+			)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package keboola
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	acc "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackendEngineSchemas(t *testing.T) {
+	ctx := context.Background()
+	for _, newResource := range []func() resource.Resource{
+		NewBackendSnowflakeResource,
+		NewBackendRedshiftResource,
+		NewBackendSynapseResource,
+		NewBackendExasolResource,
+		NewBackendTeradataResource,
+	} {
+		resp := &resource.SchemaResponse{}
+		newResource().Schema(ctx, resource.SchemaRequest{}, resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.False(t, resp.Schema.ValidateImplementation(ctx).HasError())
+	}
+}
+
+func TestBackendEngineConfigValidator(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := &resource.SchemaResponse{}
+	NewBackendResource().Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	validate := func(values map[string]tftypes.Value) []string {
+		attributes := map[string]tftypes.Value{}
+		for name, attributeType := range objectType.AttributeTypes {
+			attributes[name] = tftypes.NewValue(attributeType, nil)
+		}
+		for name, value := range values {
+			attributes[name] = value
+		}
+		req := resource.ValidateConfigRequest{Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(objectType, attributes),
+		}}
+		resp := &resource.ValidateConfigResponse{}
+		backendEngineConfigValidator{}.ValidateResource(ctx, req, resp)
+
+		var errs []string
+		for _, d := range resp.Diagnostics.Errors() {
+			errs = append(errs, d.Detail())
+		}
+		return errs
+	}
+	str := func(v string) tftypes.Value { return tftypes.NewValue(tftypes.String, v) }
+
+	assert.Empty(t, validate(map[string]tftypes.Value{"backend": str("snowflake"), "warehouse": str("WH")}))
+	assert.Empty(t, validate(map[string]tftypes.Value{"backend": str("synapse"), "database": str("db"), "use_synapse_managed_identity": tftypes.NewValue(tftypes.Bool, true)}))
+	assert.Empty(t, validate(map[string]tftypes.Value{"backend": str("redshift")}))
+	assert.Empty(t, validate(map[string]tftypes.Value{"backend": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)}))
+	assert.Empty(t, validate(map[string]tftypes.Value{"backend": str("snowflake"), "warehouse": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)}))
+
+	assert.Equal(t,
+		[]string{`The "warehouse" attribute is required for the snowflake backend.`},
+		validate(map[string]tftypes.Value{"backend": str("snowflake")}),
+	)
+	assert.Equal(t,
+		[]string{`The "database" attribute is required for the teradata backend.`},
+		validate(map[string]tftypes.Value{"backend": str("teradata")}),
+	)
+	assert.Equal(t,
+		[]string{
+			`The "warehouse" attribute is not supported for the exasol backend.`,
+			`The "use_dynamic_backends" attribute is not supported for the exasol backend.`,
+		},
+		validate(map[string]tftypes.Value{"backend": str("exasol"), "warehouse": str("WH"), "use_dynamic_backends": tftypes.NewValue(tftypes.Bool, true)}),
+	)
+}
+
+func TestAccBackendEngineResources(t *testing.T) {
+	cases := []struct {
+		engine string
+		extra  string
+		checks []acc.TestCheckFunc
+	}{
+		{engine: "redshift"},
+		{engine: "exasol"},
+		{
+			engine: "synapse",
+			extra: `
+  database                     = "TF_TEST"
+  use_synapse_managed_identity = true`,
+			checks: []acc.TestCheckFunc{
+				acc.TestCheckResourceAttr("keboola-management_backend_synapse.test", "use_synapse_managed_identity", "true"),
+			},
+		},
+		{
+			engine: "teradata",
+			extra: `
+  database = "TF_TEST"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.engine, func(t *testing.T) {
+			fake := newFakeAPI(t)
+			name := "keboola-management_backend_" + tc.engine + ".test"
+			var id string
+			config := fake.providerConfig() + fmt.Sprintf(`
+resource "keboola-management_backend_%s" "test" {
+  host             = "tf-test.example.com"
+  username         = "tf_test_user"
+  password         = "secret"
+  password_version = 1
+  region           = "us-east-1"
+  owner            = "tf-test-owner"%s
+}
+`, tc.engine, tc.extra)
+
+			acc.Test(t, acc.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				// The password is a write-only attribute
+				TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+					tfversion.SkipBelow(tfversion.Version1_11_0),
+				},
+				CheckDestroy: fake.checkDestroy(fakeBackends),
+				Steps: []acc.TestStep{
+					// Create and read
+					{
+						Config: config,
+						Check: acc.ComposeAggregateTestCheckFunc(append([]acc.TestCheckFunc{
+							testAccCaptureID(name, &id),
+							acc.TestCheckNoResourceAttr(name, "password"),
+							fake.checkField(fakeBackends, &id, "backend", tc.engine),
+							fake.checkSecret(fakeBackends, &id, "password", "secret"),
+						}, tc.checks...)...),
+					},
+					// Import, the password version is not known to the API
+					{
+						ResourceName:            name,
+						ImportState:             true,
+						ImportStateVerify:       true,
+						ImportStateVerifyIgnore: []string{"password_version"},
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccBackendResource_engineValidation(t *testing.T) {
+	fake := newFakeAPI(t)
+
+	acc.Test(t, acc.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []acc.TestStep{
+			{
+				Config: fake.providerConfig() + `
+resource "keboola-management_backend" "test" {
+  backend  = "snowflake"
+  host     = "tf-test.snowflakecomputing.com"
+  username = "tf_test_user"
+  password = "secret"
+  region   = "us-east-1"
+  owner    = "tf-test-owner"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`The "warehouse" attribute is required for the snowflake backend`),
+			},
+			{
+				Config: fake.providerConfig() + `
+resource "keboola-management_backend" "test" {
+  backend  = "bigquery"
+  host     = "tf-test.example.com"
+  username = "tf_test_user"
+  password = "secret"
+  region   = "us-east-1"
+  owner    = "tf-test-owner"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+		},
+	})
+}
@@ -0,0 +1,36 @@
+package keboola
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ resource.Resource                = &backendEngineResource[backendExasolResourceModel, *backendExasolResourceModel]{}
+	_ resource.ResourceWithConfigure   = &backendEngineResource[backendExasolResourceModel, *backendExasolResourceModel]{}
+	_ resource.ResourceWithImportState = &backendEngineResource[backendExasolResourceModel, *backendExasolResourceModel]{}
+)
+
+// NewBackendExasolResource returns a new Exasol backend resource instance.
+func NewBackendExasolResource() resource.Resource {
+	return &backendEngineResource[backendExasolResourceModel, *backendExasolResourceModel]{
+		engine: "exasol",
+		title:  "Exasol",
+	}
+}
+
+// backendExasolResourceModel maps the resource schema data, Exasol has no engine-specific attributes.
+type backendExasolResourceModel struct {
+	backendEngineCommonModel
+}
+
+func (m *backendExasolResourceModel) common() *backendEngineCommonModel {
+	return &m.backendEngineCommonModel
+}
+
+func (m *backendExasolResourceModel) setCreateRequest(_ *management.CreateANewBackendRequest) {}
+
+func (m *backendExasolResourceModel) setUpdateRequest(_ *management.StorageBackendUpdate) {}
+
+func (m *backendExasolResourceModel) setDetail(_ map[string]interface{}) {}
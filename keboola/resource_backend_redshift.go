@@ -0,0 +1,36 @@
+package keboola
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ resource.Resource                = &backendEngineResource[backendRedshiftResourceModel, *backendRedshiftResourceModel]{}
+	_ resource.ResourceWithConfigure   = &backendEngineResource[backendRedshiftResourceModel, *backendRedshiftResourceModel]{}
+	_ resource.ResourceWithImportState = &backendEngineResource[backendRedshiftResourceModel, *backendRedshiftResourceModel]{}
+)
+
+// NewBackendRedshiftResource returns a new Redshift backend resource instance.
+func NewBackendRedshiftResource() resource.Resource {
+	return &backendEngineResource[backendRedshiftResourceModel, *backendRedshiftResourceModel]{
+		engine: "redshift",
+		title:  "Redshift",
+	}
+}
+
+// backendRedshiftResourceModel maps the resource schema data, Redshift has no engine-specific attributes.
+type backendRedshiftResourceModel struct {
+	backendEngineCommonModel
+}
+
+func (m *backendRedshiftResourceModel) common() *backendEngineCommonModel {
+	return &m.backendEngineCommonModel
+}
+
+func (m *backendRedshiftResourceModel) setCreateRequest(_ *management.CreateANewBackendRequest) {}
+
+func (m *backendRedshiftResourceModel) setUpdateRequest(_ *management.StorageBackendUpdate) {}
+
+func (m *backendRedshiftResourceModel) setDetail(_ map[string]interface{}) {}
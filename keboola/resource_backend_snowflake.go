@@ -0,0 +1,75 @@
+package keboola
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ resource.Resource                = &backendEngineResource[backendSnowflakeResourceModel, *backendSnowflakeResourceModel]{}
+	_ resource.ResourceWithConfigure   = &backendEngineResource[backendSnowflakeResourceModel, *backendSnowflakeResourceModel]{}
+	_ resource.ResourceWithImportState = &backendEngineResource[backendSnowflakeResourceModel, *backendSnowflakeResourceModel]{}
+)
+
+// NewBackendSnowflakeResource returns a new Snowflake backend resource instance.
+func NewBackendSnowflakeResource() resource.Resource {
+	return &backendEngineResource[backendSnowflakeResourceModel, *backendSnowflakeResourceModel]{
+		engine: "snowflake",
+		title:  "Snowflake",
+		attributes: map[string]schema.Attribute{
+			"warehouse": schema.StringAttribute{
+				Description: "Snowflake warehouse.",
+				Required:    true,
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"use_dynamic_backends": schema.BoolAttribute{
+				Description: "Assign dynamic backends to new projects automatically.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// backendSnowflakeResourceModel maps the resource schema data.
+type backendSnowflakeResourceModel struct {
+	backendEngineCommonModel
+	Warehouse          types.String `tfsdk:"warehouse"`
+	UseDynamicBackends types.Bool   `tfsdk:"use_dynamic_backends"`
+}
+
+func (m *backendSnowflakeResourceModel) common() *backendEngineCommonModel {
+	return &m.backendEngineCommonModel
+}
+
+func (m *backendSnowflakeResourceModel) setCreateRequest(req *management.CreateANewBackendRequest) {
+	req.Warehouse = m.Warehouse.ValueStringPointer()
+	if !m.UseDynamicBackends.IsNull() {
+		req.UseDynamicBackends = m.UseDynamicBackends.ValueBoolPointer()
+	}
+}
+
+func (m *backendSnowflakeResourceModel) setUpdateRequest(req *management.StorageBackendUpdate) {
+	if !m.UseDynamicBackends.IsNull() {
+		req.UseDynamicBackends = m.UseDynamicBackends.ValueBoolPointer()
+	}
+}
+
+func (m *backendSnowflakeResourceModel) setDetail(backend map[string]interface{}) {
+	if warehouse, ok := backendDetailString(backend, "warehouse"); ok {
+		m.Warehouse = warehouse
+	}
+	// The flag is refreshed only when it is managed, the API returns it for every backend
+	if useDynamic, ok := backendDetailBool(backend, "useDynamicBackends"); ok && !m.UseDynamicBackends.IsNull() {
+		m.UseDynamicBackends = useDynamic
+	}
+}
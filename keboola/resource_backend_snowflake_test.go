@@ -0,0 +1,94 @@
+package keboola
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccBackendSnowflakeResource(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_backend_snowflake.test"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// The password is a write-only attribute
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		CheckDestroy: fake.checkDestroy(fakeBackends),
+		Steps: []resource.TestStep{
+			// Validation of the required engine attributes
+			{
+				Config:      fake.providerConfig() + testAccBackendSnowflakeConfig("tf_test_user", "secret", 1, ""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Attribute warehouse string length must be at least 1`),
+			},
+			// Create and read
+			{
+				Config: fake.providerConfig() + testAccBackendSnowflakeConfig("tf_test_user", "secret", 1, "TF_TEST"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckNoResourceAttr(name, "password"),
+					fake.checkSecret(fakeBackends, &id, "password", "secret"),
+					resource.TestCheckResourceAttr(name, "warehouse", "TF_TEST"),
+					resource.TestCheckResourceAttr(name, "use_dynamic_backends", "true"),
+					fake.checkField(fakeBackends, &id, "backend", "snowflake"),
+					fake.checkField(fakeBackends, &id, "warehouse", "TF_TEST"),
+				),
+			},
+			// Import, the password version is not known to the API
+			{
+				ResourceName:            name,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password_version"},
+			},
+			// A new password without a new version is not sent
+			{
+				Config:   fake.providerConfig() + testAccBackendSnowflakeConfig("tf_test_user", "ignored", 1, "TF_TEST"),
+				PlanOnly: true,
+			},
+			// Update of the credentials in place, the password is rotated by its version
+			{
+				Config: fake.providerConfig() + testAccBackendSnowflakeConfig("tf_test_user_renamed", "rotated", 2, "TF_TEST"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", id),
+					resource.TestCheckResourceAttr(name, "password_version", "2"),
+					fake.checkField(fakeBackends, &id, "username", "tf_test_user_renamed"),
+					fake.checkSecret(fakeBackends, &id, "password", "rotated"),
+				),
+			},
+			// Drift
+			{
+				PreConfig: func() {
+					fake.update(fakeBackends, id, func(data map[string]interface{}) {
+						data["warehouse"] = "CHANGED"
+					})
+				},
+				Config:             fake.providerConfig() + testAccBackendSnowflakeConfig("tf_test_user_renamed", "rotated", 2, "TF_TEST"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccBackendSnowflakeConfig(username, password string, passwordVersion int, warehouse string) string {
+	return fmt.Sprintf(`
+resource "keboola-management_backend_snowflake" "test" {
+  host                 = "tf-test.snowflakecomputing.com"
+  username             = %q
+  password             = %q
+  password_version     = %d
+  region               = "us-east-1"
+  owner                = "tf-test-owner"
+  warehouse            = %q
+  use_dynamic_backends = true
+}
+`, username, password, passwordVersion, warehouse)
+}
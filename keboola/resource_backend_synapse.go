@@ -0,0 +1,78 @@
+package keboola
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ resource.Resource                = &backendEngineResource[backendSynapseResourceModel, *backendSynapseResourceModel]{}
+	_ resource.ResourceWithConfigure   = &backendEngineResource[backendSynapseResourceModel, *backendSynapseResourceModel]{}
+	_ resource.ResourceWithImportState = &backendEngineResource[backendSynapseResourceModel, *backendSynapseResourceModel]{}
+)
+
+// NewBackendSynapseResource returns a new Synapse backend resource instance.
+func NewBackendSynapseResource() resource.Resource {
+	return &backendEngineResource[backendSynapseResourceModel, *backendSynapseResourceModel]{
+		engine: "synapse",
+		title:  "Synapse",
+		attributes: map[string]schema.Attribute{
+			"database": schema.StringAttribute{
+				Description: "Synapse database.",
+				Required:    true,
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"use_synapse_managed_identity": schema.BoolAttribute{
+				Description: "Use the Synapse managed identity.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+// backendSynapseResourceModel maps the resource schema data.
+type backendSynapseResourceModel struct {
+	backendEngineCommonModel
+	Database                  types.String `tfsdk:"database"`
+	UseSynapseManagedIdentity types.Bool   `tfsdk:"use_synapse_managed_identity"`
+}
+
+func (m *backendSynapseResourceModel) common() *backendEngineCommonModel {
+	return &m.backendEngineCommonModel
+}
+
+func (m *backendSynapseResourceModel) setCreateRequest(req *management.CreateANewBackendRequest) {
+	req.Database = m.Database.ValueStringPointer()
+	if !m.UseSynapseManagedIdentity.IsNull() {
+		// The API expects the flag as a string
+		useManagedIdentity := strconv.FormatBool(m.UseSynapseManagedIdentity.ValueBool())
+		req.UseSynapseManagedIdentity = &useManagedIdentity
+	}
+}
+
+func (m *backendSynapseResourceModel) setUpdateRequest(_ *management.StorageBackendUpdate) {}
+
+func (m *backendSynapseResourceModel) setDetail(backend map[string]interface{}) {
+	if database, ok := backendDetailString(backend, "database"); ok {
+		m.Database = database
+	}
+	if useManagedIdentity, ok := backendDetailBool(backend, "useSynapseManagedIdentity"); ok && !m.UseSynapseManagedIdentity.IsNull() {
+		m.UseSynapseManagedIdentity = useManagedIdentity
+	}
+}
@@ -0,0 +1,59 @@
+package keboola
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ resource.Resource                = &backendEngineResource[backendTeradataResourceModel, *backendTeradataResourceModel]{}
+	_ resource.ResourceWithConfigure   = &backendEngineResource[backendTeradataResourceModel, *backendTeradataResourceModel]{}
+	_ resource.ResourceWithImportState = &backendEngineResource[backendTeradataResourceModel, *backendTeradataResourceModel]{}
+)
+
+// NewBackendTeradataResource returns a new Teradata backend resource instance.
+func NewBackendTeradataResource() resource.Resource {
+	return &backendEngineResource[backendTeradataResourceModel, *backendTeradataResourceModel]{
+		engine: "teradata",
+		title:  "Teradata",
+		attributes: map[string]schema.Attribute{
+			"database": schema.StringAttribute{
+				Description: "Teradata database.",
+				Required:    true,
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+// backendTeradataResourceModel maps the resource schema data.
+type backendTeradataResourceModel struct {
+	backendEngineCommonModel
+	Database types.String `tfsdk:"database"`
+}
+
+func (m *backendTeradataResourceModel) common() *backendEngineCommonModel {
+	return &m.backendEngineCommonModel
+}
+
+func (m *backendTeradataResourceModel) setCreateRequest(req *management.CreateANewBackendRequest) {
+	req.Database = m.Database.ValueStringPointer()
+}
+
+func (m *backendTeradataResourceModel) setUpdateRequest(_ *management.StorageBackendUpdate) {}
+
+func (m *backendTeradataResourceModel) setDetail(backend map[string]interface{}) {
+	if database, ok := backendDetailString(backend, "database"); ok {
+		m.Database = database
+	}
+}
@@ -0,0 +1,104 @@
+package keboola
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccBackendResource(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_backend.test"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// The password is a write-only attribute
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		CheckDestroy: fake.checkDestroy(fakeBackends),
+		Steps: []resource.TestStep{
+			// Create and read, the password is not stored in the state
+			{
+				Config: fake.providerConfig() + testAccBackendConfig("tf_test_user", "secret", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckResourceAttr(name, "backend", "snowflake"),
+					resource.TestCheckNoResourceAttr(name, "password"),
+					resource.TestCheckResourceAttr(name, "password_version", "1"),
+					fake.checkField(fakeBackends, &id, "host", "tf-test.snowflakecomputing.com"),
+					fake.checkSecret(fakeBackends, &id, "password", "secret"),
+				),
+			},
+			// Import, the password version is not known to the API
+			{
+				ResourceName:            name,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password_version"},
+			},
+			// A new password without a new version is not sent
+			{
+				Config:   fake.providerConfig() + testAccBackendConfig("tf_test_user", "ignored", 1),
+				PlanOnly: true,
+			},
+			// Update
+			{
+				Config: fake.providerConfig() + testAccBackendConfig("tf_test_user_renamed", "secret", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "username", "tf_test_user_renamed"),
+					fake.checkField(fakeBackends, &id, "username", "tf_test_user_renamed"),
+				),
+			},
+			// Rotation of the password by its version
+			{
+				Config: fake.providerConfig() + testAccBackendConfig("tf_test_user_renamed", "rotated", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", id),
+					resource.TestCheckResourceAttr(name, "password_version", "2"),
+					fake.checkSecret(fakeBackends, &id, "password", "rotated"),
+				),
+			},
+			// Drift
+			{
+				PreConfig: func() {
+					fake.update(fakeBackends, id, func(data map[string]interface{}) {
+						data["host"] = "changed.snowflakecomputing.com"
+					})
+				},
+				Config:             fake.providerConfig() + testAccBackendConfig("tf_test_user_renamed", "rotated", 2),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// A backend removed outside of Terraform is created again
+			{
+				PreConfig: func() {
+					fake.remove(fakeBackends, id)
+				},
+				Config: fake.providerConfig() + testAccBackendConfig("tf_test_user_renamed", "rotated", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckNewID(name, &id),
+					fake.checkSecret(fakeBackends, &id, "password", "rotated"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBackendConfig(username, password string, passwordVersion int) string {
+	return fmt.Sprintf(`
+resource "keboola-management_backend" "test" {
+  backend          = "snowflake"
+  host             = "tf-test.snowflakecomputing.com"
+  username         = %q
+  password         = %q
+  password_version = %d
+  region           = "us-east-1"
+  owner            = "tf-test-owner"
+  warehouse        = "TF_TEST"
+}
+`, username, password, passwordVersion)
+}
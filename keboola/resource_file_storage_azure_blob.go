@@ -3,19 +3,24 @@ package keboola
 import (
 	"context"
 	"fmt"
+	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
 )
 
-// Azure Blob Storage File Storage resource (Create and Read only)
+// Azure Blob Storage File Storage resource, Delete is not supported by the API
 var (
-	_ resource.Resource              = &fileStorageAzureBlobResource{}
-	_ resource.ResourceWithConfigure = &fileStorageAzureBlobResource{}
+	_ resource.Resource                = &fileStorageAzureBlobResource{}
+	_ resource.ResourceWithConfigure   = &fileStorageAzureBlobResource{}
+	_ resource.ResourceWithImportState = &fileStorageAzureBlobResource{}
+	_ resource.ResourceWithModifyPlan  = &fileStorageAzureBlobResource{}
 )
 
 func NewFileStorageAzureBlobResource() resource.Resource {
@@ -27,11 +32,13 @@ type fileStorageAzureBlobResource struct {
 }
 
 type fileStorageAzureBlobResourceModel struct {
-	ID            types.String `tfsdk:"id"`
-	AccountName   types.String `tfsdk:"account_name"`
-	AccountKey    types.String `tfsdk:"account_key"`
-	Owner         types.String `tfsdk:"owner"`
-	ContainerName types.String `tfsdk:"container_name"`
+	ID                types.String `tfsdk:"id"`
+	AccountName       types.String `tfsdk:"account_name"`
+	AccountKey        types.String `tfsdk:"account_key"`
+	AccountKeyVersion types.Int64  `tfsdk:"account_key_version"`
+	Owner             types.String `tfsdk:"owner"`
+	ContainerName     types.String `tfsdk:"container_name"`
+	IsDefault         types.Bool   `tfsdk:"is_default"`
 }
 
 func (r *fileStorageAzureBlobResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
@@ -47,7 +54,9 @@ func (r *fileStorageAzureBlobResource) Metadata(_ context.Context, req resource.
 
 func (r *fileStorageAzureBlobResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manages Azure Blob Storage file storage. Only Create and Read are supported.",
+		Description: "Manages Azure Blob Storage file storage. The API can neither update the settings nor rotate the account key of a storage, " +
+			"so only is_default is updated in place and a change of the other attributes fails at plan time. " +
+			"Deletion is not supported by the API, the storage is only removed from the state.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "Storage ID.",
@@ -61,9 +70,15 @@ func (r *fileStorageAzureBlobResource) Schema(_ context.Context, _ resource.Sche
 				Required:    true,
 			},
 			"account_key": schema.StringAttribute{
-				Description: "Azure storage account key.",
-				Required:    true,
-				Sensitive:   true,
+				Description: "Azure storage account key. The key is write-only and is not stored in the state, " +
+					"it is sent only when the storage is registered.",
+				Required:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+			"account_key_version": schema.Int64Attribute{
+				Description: "Version of the account key. The API cannot rotate the key, so a change fails at plan time. A version set after import is only recorded.",
+				Optional:    true,
 			},
 			"owner": schema.StringAttribute{
 				Description: "Associated Azure account owner.",
@@ -73,6 +88,15 @@ func (r *fileStorageAzureBlobResource) Schema(_ context.Context, _ resource.Sche
 				Description: "Azure Blob container name.",
 				Optional:    true,
 			},
+			"is_default": schema.BoolAttribute{
+				Description: "Whether the storage is the default for new projects. The default cannot be unset, " +
+					"mark another storage as the default instead.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
@@ -81,6 +105,9 @@ func (r *fileStorageAzureBlobResource) Create(ctx context.Context, req resource.
 	var plan fileStorageAzureBlobResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	// The account key is write-only, it is available only in the configuration
+	var accountKey types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("account_key"), &accountKey)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -88,7 +115,7 @@ func (r *fileStorageAzureBlobResource) Create(ctx context.Context, req resource.
 	apiReq := management.CreateNewAzureBlobStorageRequest{
 		AccountName: plan.AccountName.ValueString(),
 		Owner:       plan.Owner.ValueString(),
-		AccountKey:  plan.AccountKey.ValueString(),
+		AccountKey:  accountKey.ValueString(),
 	}
 	if !plan.ContainerName.IsNull() && plan.ContainerName.ValueString() != "" {
 		container := plan.ContainerName.ValueString()
@@ -103,8 +130,20 @@ func (r *fileStorageAzureBlobResource) Create(ctx context.Context, req resource.
 		)
 		return
 	}
-	plan.ID = types.StringValue(fmt.Sprintf("%v", apiResp.Id))
+	plan.ID = types.StringValue(apiResp.GetId())
+
+	// A new storage is not the default until it is marked
+	isDefault := plan.IsDefault
+	plan.IsDefault = types.BoolValue(false)
+	if isDefault.ValueBool() {
+		diags = r.client.updateFileStorageDefault(ctx, fileStorageAzureBlob, "Azure Blob", plan.ID.ValueString(), isDefault, plan.IsDefault)
+		resp.Diagnostics.Append(diags...)
+		if !diags.HasError() {
+			plan.IsDefault = isDefault
+		}
+	}
 
+	// The storage exists even if it could not be marked as the default, so it is always saved to the state
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 }
@@ -137,20 +176,20 @@ func (r *fileStorageAzureBlobResource) Read(ctx context.Context, req resource.Re
 		if !ok {
 			continue
 		}
-		if state.ID.ValueString() == fmt.Sprintf("%v", id) {
+		if state.ID.ValueString() == strconv.FormatInt(int64(id), 10) {
 			// Map fields
 			if v, ok := storage["accountName"].(string); ok {
 				state.AccountName = types.StringValue(v)
 			}
-			if v, ok := storage["accountKey"].(string); ok {
-				state.AccountKey = types.StringValue(v)
-			}
 			if v, ok := storage["owner"].(string); ok {
 				state.Owner = types.StringValue(v)
 			}
 			if v, ok := storage["containerName"].(string); ok {
 				state.ContainerName = types.StringValue(v)
 			}
+			if v, ok := storage["isDefault"].(bool); ok {
+				state.IsDefault = types.BoolValue(v)
+			}
 			found = true
 			break
 		}
@@ -170,7 +209,33 @@ func (r *fileStorageAzureBlobResource) Delete(ctx context.Context, req resource.
 	resp.Diagnostics.AddWarning("Delete not supported", "Deletion of Azure Blob file storage is not supported by the Keboola API.")
 }
 
-// Update is a no-op. Update is not supported by the API.
+// ModifyPlan rejects the changes the API cannot apply, only is_default can be updated.
+func (r *fileStorageAzureBlobResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	denyFileStorageChanges(ctx, req, resp, "Azure Blob", path.Root("account_key_version"),
+		path.Root("account_name"), path.Root("owner"), path.Root("container_name"))
+}
+
+// Update marks the storage as the default, ModifyPlan rejects the changes of the other attributes.
 func (r *fileStorageAzureBlobResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddWarning("Update not supported", "Update of Azure Blob file storage is not supported by the Keboola API.")
+	var plan, state fileStorageAzureBlobResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags := r.client.updateFileStorageDefault(ctx, fileStorageAzureBlob, "Azure Blob", plan.ID.ValueString(), plan.IsDefault, state.IsDefault)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// ImportState imports an existing Azure Blob file storage by its ID.
+func (r *fileStorageAzureBlobResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The account key is write-only and is not imported
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
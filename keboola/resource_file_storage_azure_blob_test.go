@@ -0,0 +1,81 @@
+package keboola
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccFileStorageAzureBlobResource(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_file_storage_azure_blob.test"
+	config := fake.providerConfig() + testAccFileStorageAzureBlobConfig("secret", 1)
+	rotatedConfig := fake.providerConfig() + testAccFileStorageAzureBlobConfig("rotated", 2)
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// The account key is a write-only attribute
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			// Create and read
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckNoResourceAttr(name, "account_key"),
+					fake.checkField(fakeABSStorages, &id, "containerName", "tf-test"),
+					fake.checkSecret(fakeABSStorages, &id, "accountKey", "secret"),
+				),
+			},
+			// Import, the account key version is not known to the API
+			{
+				ResourceName:            name,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"account_key_version"},
+			},
+			// The API cannot rotate the account key, a new key version fails the plan and the storage is kept
+			{
+				Config:      rotatedConfig,
+				ExpectError: regexp.MustCompile(`Cannot change account_key_version of the Azure Blob file storage`),
+			},
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPtr(name, "id", &id),
+					fake.checkSecret(fakeABSStorages, &id, "accountKey", "secret"),
+					testAccCheckFileStorageCount(fake, fakeABSStorages, 1),
+				),
+			},
+			// Drift of a setting cannot be reverted
+			{
+				PreConfig: func() {
+					fake.update(fakeABSStorages, id, func(data map[string]interface{}) {
+						data["containerName"] = "changed"
+					})
+				},
+				Config:      config,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Cannot change container_name of the Azure Blob file storage`),
+			},
+		},
+	})
+}
+
+func testAccFileStorageAzureBlobConfig(accountKey string, accountKeyVersion int) string {
+	return fmt.Sprintf(`
+resource "keboola-management_file_storage_azure_blob" "test" {
+  account_name        = "tftest"
+  account_key         = %q
+  account_key_version = %d
+  owner               = "keboola"
+  container_name      = "tf-test"
+}
+`, accountKey, accountKeyVersion)
+}
@@ -3,19 +3,24 @@ package keboola
 import (
 	"context"
 	"fmt"
+	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
 )
 
-// GCP Cloud Storage File Storage resource (Create and Read only)
+// GCP Cloud Storage File Storage resource, Delete is not supported by the API
 var (
-	_ resource.Resource              = &fileStorageGCSResource{}
-	_ resource.ResourceWithConfigure = &fileStorageGCSResource{}
+	_ resource.Resource                = &fileStorageGCSResource{}
+	_ resource.ResourceWithConfigure   = &fileStorageGCSResource{}
+	_ resource.ResourceWithImportState = &fileStorageGCSResource{}
+	_ resource.ResourceWithModifyPlan  = &fileStorageGCSResource{}
 )
 
 func NewFileStorageGCSResource() resource.Resource {
@@ -31,6 +36,7 @@ type fileStorageGCSResourceModel struct {
 	FilesBucket    types.String                    `tfsdk:"files_bucket"`
 	Owner          types.String                    `tfsdk:"owner"`
 	Region         types.String                    `tfsdk:"region"`
+	IsDefault      types.Bool                      `tfsdk:"is_default"`
 	GcsCredentials *fileStorageGCSCredentialsModel `tfsdk:"gcs_credentials"`
 }
 
@@ -39,6 +45,7 @@ type fileStorageGCSCredentialsModel struct {
 	ProjectID               types.String `tfsdk:"project_id"`
 	PrivateKeyID            types.String `tfsdk:"private_key_id"`
 	PrivateKey              types.String `tfsdk:"private_key"`
+	PrivateKeyVersion       types.Int64  `tfsdk:"private_key_version"`
 	ClientEmail             types.String `tfsdk:"client_email"`
 	ClientID                types.String `tfsdk:"client_id"`
 	AuthURI                 types.String `tfsdk:"auth_uri"`
@@ -47,6 +54,25 @@ type fileStorageGCSCredentialsModel struct {
 	ClientX509CertURL       types.String `tfsdk:"client_x509_cert_url"`
 }
 
+// apiCredentials converts the credentials to the API model, nil if the block is not set.
+func (m *fileStorageGCSCredentialsModel) apiCredentials() *management.CreateNewGoogleCloudStorageRequestGcsCredentials {
+	if m == nil {
+		return nil
+	}
+	return &management.CreateNewGoogleCloudStorageRequestGcsCredentials{
+		Type:                    m.Type.ValueString(),
+		ProjectId:               m.ProjectID.ValueString(),
+		PrivateKeyId:            m.PrivateKeyID.ValueString(),
+		PrivateKey:              m.PrivateKey.ValueString(),
+		ClientEmail:             m.ClientEmail.ValueString(),
+		ClientId:                m.ClientID.ValueString(),
+		AuthUri:                 m.AuthURI.ValueString(),
+		TokenUri:                m.TokenURI.ValueString(),
+		AuthProviderX509CertUrl: m.AuthProviderX509CertURL.ValueString(),
+		ClientX509CertUrl:       m.ClientX509CertURL.ValueString(),
+	}
+}
+
 func (r *fileStorageGCSResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -60,7 +86,9 @@ func (r *fileStorageGCSResource) Metadata(_ context.Context, req resource.Metada
 
 func (r *fileStorageGCSResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manages GCP Cloud Storage file storage. Only Create and Read are supported.",
+		Description: "Manages GCP Cloud Storage file storage. The API can neither update the settings nor rotate the credentials of a storage, " +
+			"so a change of any attribute fails at plan time. " +
+			"Deletion is not supported by the API, the storage is only removed from the state.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "Storage ID.",
@@ -81,10 +109,18 @@ func (r *fileStorageGCSResource) Schema(_ context.Context, _ resource.SchemaRequ
 				Description: "GCP region.",
 				Required:    true,
 			},
+			"is_default": schema.BoolAttribute{
+				Description: "Whether the storage is the default for new projects. " +
+					"The API does not support marking a GCS storage as the default, so the flag is read-only.",
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 		Blocks: map[string]schema.Block{
 			"gcs_credentials": schema.SingleNestedBlock{
-				Description: "Service account credentials for GCS storage.",
+				Description: "Service account credentials for GCS storage. The API cannot rotate the credentials, so a change fails at plan time.",
 				Attributes: map[string]schema.Attribute{
 					"type": schema.StringAttribute{
 						Description: "Credential type.",
@@ -99,9 +135,15 @@ func (r *fileStorageGCSResource) Schema(_ context.Context, _ resource.SchemaRequ
 						Required:    true,
 					},
 					"private_key": schema.StringAttribute{
-						Description: "Private key.",
-						Required:    true,
-						Sensitive:   true,
+						Description: "Private key. The key is write-only and is not stored in the state, " +
+							"it is sent only when the storage is registered.",
+						Required:  true,
+						Sensitive: true,
+						WriteOnly: true,
+					},
+					"private_key_version": schema.Int64Attribute{
+						Description: "Version of the private key. The API cannot rotate the key, so a change fails at plan time. A version set after import is only recorded.",
+						Optional:    true,
 					},
 					"client_email": schema.StringAttribute{
 						Description: "Client email.",
@@ -134,34 +176,19 @@ func (r *fileStorageGCSResource) Schema(_ context.Context, _ resource.SchemaRequ
 }
 
 func (r *fileStorageGCSResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan fileStorageGCSResourceModel
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+	var plan, config fileStorageGCSResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	// The private key is write-only, the credentials are taken from the configuration
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	var creds *management.CreateNewGoogleCloudStorageRequestGcsCredentials
-	if plan.GcsCredentials != nil {
-		creds = &management.CreateNewGoogleCloudStorageRequestGcsCredentials{
-			Type:                    plan.GcsCredentials.Type.ValueString(),
-			ProjectId:               plan.GcsCredentials.ProjectID.ValueString(),
-			PrivateKeyId:            plan.GcsCredentials.PrivateKeyID.ValueString(),
-			PrivateKey:              plan.GcsCredentials.PrivateKey.ValueString(),
-			ClientEmail:             plan.GcsCredentials.ClientEmail.ValueString(),
-			ClientId:                plan.GcsCredentials.ClientID.ValueString(),
-			AuthUri:                 plan.GcsCredentials.AuthURI.ValueString(),
-			TokenUri:                plan.GcsCredentials.TokenURI.ValueString(),
-			AuthProviderX509CertUrl: plan.GcsCredentials.AuthProviderX509CertURL.ValueString(),
-			ClientX509CertUrl:       plan.GcsCredentials.ClientX509CertURL.ValueString(),
-		}
-	}
-
 	apiReq := management.CreateNewGoogleCloudStorageRequest{
 		FilesBucket:    plan.FilesBucket.ValueString(),
 		Owner:          plan.Owner.ValueString(),
 		Region:         plan.Region.ValueString(),
-		GcsCredentials: creds,
+		GcsCredentials: config.GcsCredentials.apiCredentials(),
 	}
 
 	apiResp, _, err := r.client.API.SUPERFileStorageManagementAPI.CreateNewGoogleCloudStorage(ctx).CreateNewGoogleCloudStorageRequest(apiReq).Execute()
@@ -172,10 +199,12 @@ func (r *fileStorageGCSResource) Create(ctx context.Context, req resource.Create
 		)
 		return
 	}
-	plan.ID = types.StringValue(fmt.Sprintf("%v", apiResp.Id))
+	plan.ID = types.StringValue(strconv.FormatInt(int64(apiResp.GetId()), 10))
 
-	diags = resp.State.Set(ctx, plan)
-	resp.Diagnostics.Append(diags...)
+	// A new storage is not the default, the flag is read back from the list of storages
+	plan.IsDefault = types.BoolValue(false)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
 func (r *fileStorageGCSResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -206,7 +235,7 @@ func (r *fileStorageGCSResource) Read(ctx context.Context, req resource.ReadRequ
 		if !ok {
 			continue
 		}
-		if state.ID.ValueString() == fmt.Sprintf("%v", id) {
+		if state.ID.ValueString() == strconv.FormatInt(int64(id), 10) {
 			// Map fields
 			if v, ok := storage["filesBucket"].(string); ok {
 				state.FilesBucket = types.StringValue(v)
@@ -217,36 +246,40 @@ func (r *fileStorageGCSResource) Read(ctx context.Context, req resource.ReadRequ
 			if v, ok := storage["owner"].(string); ok {
 				state.Owner = types.StringValue(v)
 			}
+			if v, ok := storage["isDefault"].(bool); ok {
+				state.IsDefault = types.BoolValue(v)
+			}
 			if creds, ok := storage["gcsCredentials"].(map[string]interface{}); ok {
+				// The API returns the credentials without the private key, keep private_key_version from state
 				credModel := &fileStorageGCSCredentialsModel{}
+				if state.GcsCredentials != nil {
+					*credModel = *state.GcsCredentials
+				}
 				if v, ok := creds["type"].(string); ok {
 					credModel.Type = types.StringValue(v)
 				}
-				if v, ok := creds["projectId"].(string); ok {
+				if v, ok := creds["project_id"].(string); ok {
 					credModel.ProjectID = types.StringValue(v)
 				}
-				if v, ok := creds["privateKeyId"].(string); ok {
+				if v, ok := creds["private_key_id"].(string); ok {
 					credModel.PrivateKeyID = types.StringValue(v)
 				}
-				if v, ok := creds["privateKey"].(string); ok {
-					credModel.PrivateKey = types.StringValue(v)
-				}
-				if v, ok := creds["clientEmail"].(string); ok {
+				if v, ok := creds["client_email"].(string); ok {
 					credModel.ClientEmail = types.StringValue(v)
 				}
-				if v, ok := creds["clientId"].(string); ok {
+				if v, ok := creds["client_id"].(string); ok {
 					credModel.ClientID = types.StringValue(v)
 				}
-				if v, ok := creds["authUri"].(string); ok {
+				if v, ok := creds["auth_uri"].(string); ok {
 					credModel.AuthURI = types.StringValue(v)
 				}
-				if v, ok := creds["tokenUri"].(string); ok {
+				if v, ok := creds["token_uri"].(string); ok {
 					credModel.TokenURI = types.StringValue(v)
 				}
-				if v, ok := creds["authProviderX509CertUrl"].(string); ok {
+				if v, ok := creds["auth_provider_x509_cert_url"].(string); ok {
 					credModel.AuthProviderX509CertURL = types.StringValue(v)
 				}
-				if v, ok := creds["clientX509CertUrl"].(string); ok {
+				if v, ok := creds["client_x509_cert_url"].(string); ok {
 					credModel.ClientX509CertURL = types.StringValue(v)
 				}
 				state.GcsCredentials = credModel
@@ -270,7 +303,31 @@ func (r *fileStorageGCSResource) Delete(ctx context.Context, req resource.Delete
 	resp.Diagnostics.AddWarning("Delete not supported", "Deletion of GCS file storage is not supported by the Keboola API.")
 }
 
-// Update is a no-op. Update is not supported by the API.
+// ModifyPlan rejects all changes, the API cannot update a GCS storage.
+func (r *fileStorageGCSResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	credentials := path.Root("gcs_credentials")
+	denyFileStorageChanges(ctx, req, resp, "GCS", credentials.AtName("private_key_version"),
+		path.Root("files_bucket"), path.Root("owner"), path.Root("region"),
+		credentials.AtName("type"), credentials.AtName("project_id"), credentials.AtName("private_key_id"),
+		credentials.AtName("client_email"), credentials.AtName("client_id"), credentials.AtName("auth_uri"),
+		credentials.AtName("token_uri"), credentials.AtName("auth_provider_x509_cert_url"), credentials.AtName("client_x509_cert_url"))
+}
+
+// Update only saves the plan, ModifyPlan rejects the changes of the configurable attributes.
 func (r *fileStorageGCSResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddWarning("Update not supported", "Update of GCS file storage is not supported by the Keboola API.")
+	var plan fileStorageGCSResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// ImportState imports an existing GCS file storage by its ID.
+func (r *fileStorageGCSResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The private key is write-only and is not imported
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
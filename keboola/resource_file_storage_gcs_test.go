@@ -0,0 +1,83 @@
+package keboola
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccFileStorageGCSResource(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_file_storage_gcs.test"
+	config := fake.providerConfig() + testAccFileStorageGCSConfig("tf-test@example.iam.gserviceaccount.com", 1)
+	rotatedConfig := fake.providerConfig() + testAccFileStorageGCSConfig("tf-test-rotated@example.iam.gserviceaccount.com", 2)
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// The private key is a write-only attribute
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			// Create and read, the private key is not stored in the state
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckResourceAttr(name, "gcs_credentials.private_key_id", "key-1"),
+					resource.TestCheckNoResourceAttr(name, "gcs_credentials.private_key"),
+					fake.checkField(fakeGCSStorages, &id, "region", "us-east1"),
+					fake.checkSecret(fakeGCSStorages, &id, "privateKey", testAccGCPPrivateKey(1)),
+					resource.TestCheckResourceAttr(name, "is_default", "false"),
+				),
+			},
+			// Import, the private key version is not known to the API
+			{
+				ResourceName:            name,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"gcs_credentials.private_key_version"},
+			},
+			// The API cannot rotate the credentials, new credentials fail the plan and the storage is kept
+			{
+				Config:      rotatedConfig,
+				ExpectError: regexp.MustCompile(`Cannot change gcs_credentials.client_email of the GCS file storage`),
+			},
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPtr(name, "id", &id),
+					fake.checkSecret(fakeGCSStorages, &id, "privateKey", testAccGCPPrivateKey(1)),
+					testAccCheckFileStorageCount(fake, fakeGCSStorages, 1),
+				),
+			},
+			// Drift of a setting cannot be reverted
+			{
+				PreConfig: func() {
+					fake.update(fakeGCSStorages, id, func(data map[string]interface{}) {
+						data["region"] = "europe-west1"
+					})
+				},
+				Config:      config,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Cannot change region of the GCS file storage`),
+			},
+		},
+	})
+}
+
+func testAccFileStorageGCSConfig(clientEmail string, privateKeyVersion int) string {
+	return fmt.Sprintf(`
+resource "keboola-management_file_storage_gcs" "test" {
+  files_bucket = "tf-test-bucket"
+  owner        = "keboola"
+  region       = "us-east1"
+
+  gcs_credentials {%s  }
+}
+`, testAccGCPCredentials(clientEmail, privateKeyVersion))
+}
@@ -3,19 +3,24 @@ package keboola
 import (
 	"context"
 	"fmt"
+	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
 )
 
-// AWS S3 File Storage resource (Create and Read only)
+// AWS S3 File Storage resource, Delete is not supported by the API
 var (
-	_ resource.Resource              = &fileStorageS3Resource{}
-	_ resource.ResourceWithConfigure = &fileStorageS3Resource{}
+	_ resource.Resource                = &fileStorageS3Resource{}
+	_ resource.ResourceWithConfigure   = &fileStorageS3Resource{}
+	_ resource.ResourceWithImportState = &fileStorageS3Resource{}
+	_ resource.ResourceWithModifyPlan  = &fileStorageS3Resource{}
 )
 
 func NewFileStorageS3Resource() resource.Resource {
@@ -27,12 +32,14 @@ type fileStorageS3Resource struct {
 }
 
 type fileStorageS3ResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	AwsKey      types.String `tfsdk:"aws_key"`
-	AwsSecret   types.String `tfsdk:"aws_secret"`
-	FilesBucket types.String `tfsdk:"files_bucket"`
-	Region      types.String `tfsdk:"region"`
-	Owner       types.String `tfsdk:"owner"`
+	ID               types.String `tfsdk:"id"`
+	AwsKey           types.String `tfsdk:"aws_key"`
+	AwsSecret        types.String `tfsdk:"aws_secret"`
+	AwsSecretVersion types.Int64  `tfsdk:"aws_secret_version"`
+	FilesBucket      types.String `tfsdk:"files_bucket"`
+	Region           types.String `tfsdk:"region"`
+	Owner            types.String `tfsdk:"owner"`
+	IsDefault        types.Bool   `tfsdk:"is_default"`
 }
 
 func (r *fileStorageS3Resource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
@@ -48,7 +55,9 @@ func (r *fileStorageS3Resource) Metadata(_ context.Context, req resource.Metadat
 
 func (r *fileStorageS3Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manages AWS S3 file storage. Only Create and Read are supported.",
+		Description: "Manages AWS S3 file storage. The API can neither update the settings nor rotate the credentials of a storage, " +
+			"so only is_default is updated in place and a change of the other attributes fails at plan time. " +
+			"Deletion is not supported by the API, the storage is only removed from the state.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "Storage ID.",
@@ -62,9 +71,15 @@ func (r *fileStorageS3Resource) Schema(_ context.Context, _ resource.SchemaReque
 				Required:    true,
 			},
 			"aws_secret": schema.StringAttribute{
-				Description: "AWS secret key.",
-				Required:    true,
-				Sensitive:   true,
+				Description: "AWS secret key. The secret is write-only and is not stored in the state, " +
+					"it is sent only when the storage is registered.",
+				Required:  true,
+				Sensitive: true,
+				WriteOnly: true,
+			},
+			"aws_secret_version": schema.Int64Attribute{
+				Description: "Version of the AWS secret key. The API cannot rotate the secret, so a change fails at plan time. A version set after import is only recorded.",
+				Optional:    true,
 			},
 			"files_bucket": schema.StringAttribute{
 				Description: "S3 bucket name.",
@@ -78,6 +93,15 @@ func (r *fileStorageS3Resource) Schema(_ context.Context, _ resource.SchemaReque
 				Description: "Associated AWS account owner.",
 				Required:    true,
 			},
+			"is_default": schema.BoolAttribute{
+				Description: "Whether the storage is the default for new projects. The default cannot be unset, " +
+					"mark another storage as the default instead.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
@@ -86,13 +110,16 @@ func (r *fileStorageS3Resource) Create(ctx context.Context, req resource.CreateR
 	var plan fileStorageS3ResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	// The secret is write-only, it is available only in the configuration
+	var awsSecret types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("aws_secret"), &awsSecret)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	apiReq := management.CreateNewAWSS3StorageRequest{
 		AwsKey:      plan.AwsKey.ValueString(),
-		AwsSecret:   plan.AwsSecret.ValueString(),
+		AwsSecret:   awsSecret.ValueString(),
 		FilesBucket: plan.FilesBucket.ValueString(),
 		Region:      plan.Region.ValueString(),
 		Owner:       plan.Owner.ValueString(),
@@ -106,8 +133,20 @@ func (r *fileStorageS3Resource) Create(ctx context.Context, req resource.CreateR
 		)
 		return
 	}
-	plan.ID = types.StringValue(fmt.Sprintf("%v", apiResp.Id))
+	plan.ID = types.StringValue(apiResp.Id)
+
+	// A new storage is not the default until it is marked
+	isDefault := plan.IsDefault
+	plan.IsDefault = types.BoolValue(false)
+	if isDefault.ValueBool() {
+		diags = r.client.updateFileStorageDefault(ctx, fileStorageS3, "AWS S3", plan.ID.ValueString(), isDefault, plan.IsDefault)
+		resp.Diagnostics.Append(diags...)
+		if !diags.HasError() {
+			plan.IsDefault = isDefault
+		}
+	}
 
+	// The storage exists even if it could not be marked as the default, so it is always saved to the state
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 }
@@ -140,14 +179,11 @@ func (r *fileStorageS3Resource) Read(ctx context.Context, req resource.ReadReque
 		if !ok {
 			continue
 		}
-		if state.ID.ValueString() == fmt.Sprintf("%v", id) {
+		if state.ID.ValueString() == strconv.FormatInt(int64(id), 10) {
 			// Map fields
 			if v, ok := storage["awsKey"].(string); ok {
 				state.AwsKey = types.StringValue(v)
 			}
-			if v, ok := storage["awsSecret"].(string); ok {
-				state.AwsSecret = types.StringValue(v)
-			}
 			if v, ok := storage["filesBucket"].(string); ok {
 				state.FilesBucket = types.StringValue(v)
 			}
@@ -157,6 +193,9 @@ func (r *fileStorageS3Resource) Read(ctx context.Context, req resource.ReadReque
 			if v, ok := storage["owner"].(string); ok {
 				state.Owner = types.StringValue(v)
 			}
+			if v, ok := storage["isDefault"].(bool); ok {
+				state.IsDefault = types.BoolValue(v)
+			}
 			found = true
 			break
 		}
@@ -176,7 +215,33 @@ func (r *fileStorageS3Resource) Delete(ctx context.Context, req resource.DeleteR
 	resp.Diagnostics.AddWarning("Delete not supported", "Deletion of AWS S3 file storage is not supported by the Keboola API.")
 }
 
-// Update is a no-op. Update is not supported by the API.
+// ModifyPlan rejects the changes the API cannot apply, only is_default can be updated.
+func (r *fileStorageS3Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	denyFileStorageChanges(ctx, req, resp, "AWS S3", path.Root("aws_secret_version"),
+		path.Root("aws_key"), path.Root("files_bucket"), path.Root("region"), path.Root("owner"))
+}
+
+// Update marks the storage as the default, ModifyPlan rejects the changes of the other attributes.
 func (r *fileStorageS3Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddWarning("Update not supported", "Update of AWS S3 file storage is not supported by the Keboola API.")
+	var plan, state fileStorageS3ResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags := r.client.updateFileStorageDefault(ctx, fileStorageS3, "AWS S3", plan.ID.ValueString(), plan.IsDefault, state.IsDefault)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// ImportState imports an existing AWS S3 file storage by its ID.
+func (r *fileStorageS3Resource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The AWS secret is write-only and is not imported
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
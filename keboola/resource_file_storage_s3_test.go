@@ -0,0 +1,203 @@
+package keboola
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	acc "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccFileStorageS3Resource(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_file_storage_s3.test"
+	config := fake.providerConfig() + testAccFileStorageS3Config("AKIATFTEST", "secret", 1, "tf-test-bucket", "")
+	rotatedConfig := fake.providerConfig() + testAccFileStorageS3Config("AKIATFTEST", "rotated", 2, "tf-test-bucket", "")
+	var id string
+
+	acc.Test(t, acc.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// The AWS secret is a write-only attribute
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []acc.TestStep{
+			// Create and read
+			{
+				Config: config,
+				Check: acc.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					acc.TestCheckNoResourceAttr(name, "aws_secret"),
+					fake.checkField(fakeS3Storages, &id, "filesBucket", "tf-test-bucket"),
+					fake.checkSecret(fakeS3Storages, &id, "awsSecret", "secret"),
+					acc.TestCheckResourceAttr(name, "is_default", "false"),
+				),
+			},
+			// Import, the secret version is not known to the API
+			{
+				ResourceName:            name,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"aws_secret_version"},
+			},
+			// The API cannot rotate the credentials, a new secret version or key fails the plan
+			{
+				Config:      rotatedConfig,
+				ExpectError: regexp.MustCompile(`Cannot change aws_secret_version of the AWS S3 file storage`),
+			},
+			{
+				Config:      fake.providerConfig() + testAccFileStorageS3Config("AKIATFROTATED", "secret", 1, "tf-test-bucket", ""),
+				ExpectError: regexp.MustCompile(`Cannot change aws_key of the AWS S3 file storage`),
+			},
+			// The storage is kept with its credentials, no other storage is registered
+			{
+				Config: config,
+				Check: acc.ComposeAggregateTestCheckFunc(
+					acc.TestCheckResourceAttrPtr(name, "id", &id),
+					fake.checkSecret(fakeS3Storages, &id, "awsSecret", "secret"),
+					testAccCheckFileStorageCount(fake, fakeS3Storages, 1),
+				),
+			},
+			// Drift of a setting cannot be reverted
+			{
+				PreConfig: func() {
+					fake.update(fakeS3Storages, id, func(data map[string]interface{}) {
+						data["filesBucket"] = "changed-bucket"
+					})
+				},
+				Config:      config,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Cannot change files_bucket of the AWS S3 file storage`),
+			},
+		},
+	})
+}
+
+func TestFileStorageS3ResourceModifyPlan(t *testing.T) {
+	ctx := context.Background()
+	r := NewFileStorageS3Resource().(resource.ResourceWithModifyPlan)
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	storage := func(awsKey, awsSecretVersion interface{}) tftypes.Value {
+		str := func(v interface{}) tftypes.Value { return tftypes.NewValue(tftypes.String, v) }
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"id":                 str("1"),
+			"aws_key":            str(awsKey),
+			"aws_secret":         str(nil),
+			"aws_secret_version": tftypes.NewValue(tftypes.Number, awsSecretVersion),
+			"files_bucket":       str("tf-test-bucket"),
+			"region":             str("us-east-1"),
+			"owner":              str("keboola"),
+			"is_default":         tftypes.NewValue(tftypes.Bool, false),
+		})
+	}
+	modifyPlan := func(state, plan tftypes.Value) []string {
+		resp := &resource.ModifyPlanResponse{Plan: tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan}}
+		r.ModifyPlan(ctx, resource.ModifyPlanRequest{
+			State: tfsdk.State{Schema: schemaResp.Schema, Raw: state},
+			Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan},
+		}, resp)
+		var errs []string
+		for _, d := range resp.Diagnostics.Errors() {
+			errs = append(errs, d.Summary())
+		}
+		return errs
+	}
+
+	assert.Empty(t, modifyPlan(storage("AKIATFTEST", 1), storage("AKIATFTEST", 1)))
+	assert.Equal(t, []string{"Cannot change aws_key of the AWS S3 file storage"}, modifyPlan(storage("AKIATFTEST", 1), storage("AKIATFNEW", 1)))
+	assert.Equal(t, []string{"Cannot change aws_secret_version of the AWS S3 file storage"}, modifyPlan(storage("AKIATFTEST", 1), storage("AKIATFTEST", 2)))
+	// A version set after import is only recorded
+	assert.Empty(t, modifyPlan(storage("AKIATFTEST", nil), storage("AKIATFTEST", 1)))
+	// Unknown values are checked when the plan is applied
+	assert.Empty(t, modifyPlan(storage("AKIATFTEST", 1), storage(tftypes.UnknownValue, 1)))
+	// Create and destroy
+	assert.Empty(t, modifyPlan(tftypes.NewValue(objectType, nil), storage("AKIATFTEST", 1)))
+	assert.Empty(t, modifyPlan(storage("AKIATFTEST", 1), tftypes.NewValue(objectType, nil)))
+}
+
+// testAccFileStorageS3Config returns the S3 storage, extra is added to the resource block.
+func testAccFileStorageS3Config(awsKey, awsSecret string, awsSecretVersion int, filesBucket, extra string) string {
+	return fmt.Sprintf(`
+resource "keboola-management_file_storage_s3" "test" {
+  aws_key            = %q
+  aws_secret         = %q
+  aws_secret_version = %d
+  files_bucket       = %q
+  region             = "us-east-1"
+  owner              = "keboola"
+  %s
+}
+`, awsKey, awsSecret, awsSecretVersion, filesBucket, extra)
+}
+
+func TestAccFileStorageS3Resource_default(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_file_storage_s3.test"
+	var id, otherID string
+
+	otherConfig := `
+resource "keboola-management_file_storage_s3" "other" {
+  aws_key      = "AKIATFOTHER"
+  aws_secret   = "secret"
+  files_bucket = "tf-test-other"
+  region       = "us-east-1"
+  owner        = "keboola"
+  is_default   = true
+}
+`
+	acc.Test(t, acc.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// The AWS secret is a write-only attribute
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []acc.TestStep{
+			// Create as the default
+			{
+				Config: fake.providerConfig() + testAccFileStorageS3Config("AKIATFTEST", "secret", 1, "tf-test-bucket", "is_default = true"),
+				Check: acc.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					acc.TestCheckResourceAttr(name, "is_default", "true"),
+					fake.checkField(fakeS3Storages, &id, "isDefault", true),
+				),
+			},
+			// Import reads the flag back
+			{
+				ResourceName:            name,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"aws_secret_version"},
+			},
+			// The default cannot be unset
+			{
+				Config:      fake.providerConfig() + testAccFileStorageS3Config("AKIATFTEST", "secret", 1, "tf-test-bucket", "is_default = false"),
+				ExpectError: regexp.MustCompile(`cannot be unset as default`),
+			},
+			// Another storage becomes the default, the flag is computed when not configured
+			{
+				Config: fake.providerConfig() + testAccFileStorageS3Config("AKIATFTEST", "secret", 1, "tf-test-bucket", "") + otherConfig,
+				Check: acc.ComposeAggregateTestCheckFunc(
+					testAccCaptureID("keboola-management_file_storage_s3.other", &otherID),
+					fake.checkField(fakeS3Storages, &otherID, "isDefault", true),
+					fake.checkField(fakeS3Storages, &id, "isDefault", false),
+				),
+			},
+			{
+				Config: fake.providerConfig() + testAccFileStorageS3Config("AKIATFTEST", "secret", 1, "tf-test-bucket", "") + otherConfig,
+				Check: acc.ComposeAggregateTestCheckFunc(
+					acc.TestCheckResourceAttr(name, "is_default", "false"),
+					acc.TestCheckResourceAttr("keboola-management_file_storage_s3.other", "is_default", "true"),
+				),
+			},
+		},
+	})
+}
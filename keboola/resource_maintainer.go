@@ -2,23 +2,25 @@ package keboola
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
 )
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ resource.Resource                = &maintainerResource{}
-	_ resource.ResourceWithConfigure   = &maintainerResource{}
-	_ resource.ResourceWithImportState = &maintainerResource{}
+	_ resource.Resource                 = &maintainerResource{}
+	_ resource.ResourceWithConfigure    = &maintainerResource{}
+	_ resource.ResourceWithImportState  = &maintainerResource{}
+	_ resource.ResourceWithUpgradeState = &maintainerResource{}
 )
 
 // NewMaintainerResource is a helper function to simplify provider implementation.
@@ -33,6 +35,19 @@ type maintainerResource struct {
 
 // maintainerResourceModel maps the resource schema data.
 type maintainerResourceModel struct {
+	ID                           types.String `tfsdk:"id"`
+	Name                         types.String `tfsdk:"name"`
+	DefaultConnectionRedshiftID  types.Int64  `tfsdk:"default_connection_redshift_id"`
+	DefaultConnectionSnowflakeID types.Int64  `tfsdk:"default_connection_snowflake_id"`
+	DefaultConnectionSynapseID   types.Int64  `tfsdk:"default_connection_synapse_id"`
+	DefaultConnectionExasolID    types.Int64  `tfsdk:"default_connection_exasol_id"`
+	DefaultConnectionTeradataID  types.Int64  `tfsdk:"default_connection_teradata_id"`
+	DefaultFileStorageID         types.Int64  `tfsdk:"default_file_storage_id"`
+	ZendeskURL                   types.String `tfsdk:"zendesk_url"`
+}
+
+// maintainerResourceModelV0 maps the schema version 0, which stored the IDs of the default connections as strings.
+type maintainerResourceModelV0 struct {
 	ID                           types.String `tfsdk:"id"`
 	Name                         types.String `tfsdk:"name"`
 	DefaultConnectionRedshiftID  types.String `tfsdk:"default_connection_redshift_id"`
@@ -61,6 +76,7 @@ func (r *maintainerResource) Metadata(_ context.Context, req resource.MetadataRe
 func (r *maintainerResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages a Keboola maintainer.",
+		Version:     1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "Maintainer ID.",
@@ -73,33 +89,52 @@ func (r *maintainerResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Description: "Maintainer name.",
 				Required:    true,
 			},
-			"default_connection_redshift_id": schema.StringAttribute{
+			"default_connection_redshift_id": schema.Int64Attribute{
 				Description: "Default Redshift Connection ID.",
 				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
-			"default_connection_snowflake_id": schema.StringAttribute{
+			"default_connection_snowflake_id": schema.Int64Attribute{
 				Description: "Default Snowflake Connection ID.",
 				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
-			"default_connection_synapse_id": schema.StringAttribute{
+			"default_connection_synapse_id": schema.Int64Attribute{
 				Description: "Default Synapse Connection ID.",
 				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
-			"default_connection_exasol_id": schema.StringAttribute{
+			"default_connection_exasol_id": schema.Int64Attribute{
 				Description: "Default Exasol Connection ID.",
 				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
-			"default_connection_teradata_id": schema.StringAttribute{
+			"default_connection_teradata_id": schema.Int64Attribute{
 				Description: "Default Teradata Connection ID.",
 				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
-			"default_file_storage_id": schema.StringAttribute{
+			"default_file_storage_id": schema.Int64Attribute{
 				Description: "Default File Storage ID.",
 				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
 			"zendesk_url": schema.StringAttribute{
 				Description: "Zendesk URL.",
 				Optional:    true,
+				Validators:  urlValidators(),
 			},
 		},
 	}
@@ -119,30 +154,12 @@ func (r *maintainerResource) Create(ctx context.Context, req resource.CreateRequ
 	body := management.CreateAMaintainerRequest{
 		Name: plan.Name.ValueString(),
 	}
-	if !plan.DefaultConnectionRedshiftID.IsNull() {
-		val := plan.DefaultConnectionRedshiftID.ValueString()
-		body.DefaultConnectionRedshiftId = &val
-	}
-	if !plan.DefaultConnectionSnowflakeID.IsNull() {
-		val := plan.DefaultConnectionSnowflakeID.ValueString()
-		body.DefaultConnectionSnowflakeId = &val
-	}
-	if !plan.DefaultConnectionSynapseID.IsNull() {
-		val := plan.DefaultConnectionSynapseID.ValueString()
-		body.DefaultConnectionSynapseId = &val
-	}
-	if !plan.DefaultConnectionExasolID.IsNull() {
-		val := plan.DefaultConnectionExasolID.ValueString()
-		body.DefaultConnectionExasolId = &val
-	}
-	if !plan.DefaultConnectionTeradataID.IsNull() {
-		val := plan.DefaultConnectionTeradataID.ValueString()
-		body.DefaultConnectionTeradataId = &val
-	}
-	if !plan.DefaultFileStorageID.IsNull() {
-		val := plan.DefaultFileStorageID.ValueString()
-		body.DefaultFileStorageId = &val
-	}
+	body.DefaultConnectionRedshiftId = formatInt64(plan.DefaultConnectionRedshiftID)
+	body.DefaultConnectionSnowflakeId = formatInt64(plan.DefaultConnectionSnowflakeID)
+	body.DefaultConnectionSynapseId = formatInt64(plan.DefaultConnectionSynapseID)
+	body.DefaultConnectionExasolId = formatInt64(plan.DefaultConnectionExasolID)
+	body.DefaultConnectionTeradataId = formatInt64(plan.DefaultConnectionTeradataID)
+	body.DefaultFileStorageId = formatInt64(plan.DefaultFileStorageID)
 	if !plan.ZendeskURL.IsNull() {
 		val := plan.ZendeskURL.ValueString()
 		body.ZendeskUrl = &val
@@ -167,7 +184,7 @@ func (r *maintainerResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	plan.ID = types.StringValue(fmt.Sprintf("%v", int(*apiResp.Id)))
+	plan.ID = types.StringValue(strconv.FormatInt(int64(*apiResp.Id), 10))
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -212,25 +229,25 @@ func (r *maintainerResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	// Overwrite items with refreshed state
-	state.ID = types.StringValue(fmt.Sprintf("%v", int(*apiResp.Id)))
+	state.ID = types.StringValue(strconv.FormatInt(int64(*apiResp.Id), 10))
 	// Name is not returned by API, keep local value
 	if apiResp.DefaultConnectionRedshiftId != nil {
-		state.DefaultConnectionRedshiftID = types.StringValue(fmt.Sprintf("%v", int(*apiResp.DefaultConnectionRedshiftId)))
+		state.DefaultConnectionRedshiftID = types.Int64Value(int64(*apiResp.DefaultConnectionRedshiftId))
 	}
 	if apiResp.DefaultConnectionSnowflakeId != nil {
-		state.DefaultConnectionSnowflakeID = types.StringValue(fmt.Sprintf("%v", int(*apiResp.DefaultConnectionSnowflakeId)))
+		state.DefaultConnectionSnowflakeID = types.Int64Value(int64(*apiResp.DefaultConnectionSnowflakeId))
 	}
 	if apiResp.DefaultConnectionSynapseId != nil {
-		state.DefaultConnectionSynapseID = types.StringValue(fmt.Sprintf("%v", int(*apiResp.DefaultConnectionSynapseId)))
+		state.DefaultConnectionSynapseID = types.Int64Value(int64(*apiResp.DefaultConnectionSynapseId))
 	}
 	if apiResp.DefaultConnectionExasolId != nil {
-		state.DefaultConnectionExasolID = types.StringValue(fmt.Sprintf("%v", int(*apiResp.DefaultConnectionExasolId)))
+		state.DefaultConnectionExasolID = types.Int64Value(int64(*apiResp.DefaultConnectionExasolId))
 	}
 	if apiResp.DefaultConnectionTeradataId != nil {
-		state.DefaultConnectionTeradataID = types.StringValue(fmt.Sprintf("%v", int(*apiResp.DefaultConnectionTeradataId)))
+		state.DefaultConnectionTeradataID = types.Int64Value(int64(*apiResp.DefaultConnectionTeradataId))
 	}
 	if apiResp.DefaultFileStorageId != nil {
-		state.DefaultFileStorageID = types.StringValue(fmt.Sprintf("%v", int(*apiResp.DefaultFileStorageId)))
+		state.DefaultFileStorageID = types.Int64Value(int64(*apiResp.DefaultFileStorageId))
 	}
 	if apiResp.ZendeskUrl != nil {
 		state.ZendeskURL = types.StringValue(*apiResp.ZendeskUrl)
@@ -269,30 +286,12 @@ func (r *maintainerResource) Update(ctx context.Context, req resource.UpdateRequ
 		name := plan.Name.ValueString()
 		body.Name = &name
 	}
-	if !plan.DefaultConnectionRedshiftID.IsNull() {
-		val := plan.DefaultConnectionRedshiftID.ValueString()
-		body.DefaultConnectionRedshiftId = &val
-	}
-	if !plan.DefaultConnectionSnowflakeID.IsNull() {
-		val := plan.DefaultConnectionSnowflakeID.ValueString()
-		body.DefaultConnectionSnowflakeId = &val
-	}
-	if !plan.DefaultConnectionSynapseID.IsNull() {
-		val := plan.DefaultConnectionSynapseID.ValueString()
-		body.DefaultConnectionSynapseId = &val
-	}
-	if !plan.DefaultConnectionExasolID.IsNull() {
-		val := plan.DefaultConnectionExasolID.ValueString()
-		body.DefaultConnectionExasolId = &val
-	}
-	if !plan.DefaultConnectionTeradataID.IsNull() {
-		val := plan.DefaultConnectionTeradataID.ValueString()
-		body.DefaultConnectionTeradataId = &val
-	}
-	if !plan.DefaultFileStorageID.IsNull() {
-		val := plan.DefaultFileStorageID.ValueString()
-		body.DefaultFileStorageId = &val
-	}
+	body.DefaultConnectionRedshiftId = formatInt64(plan.DefaultConnectionRedshiftID)
+	body.DefaultConnectionSnowflakeId = formatInt64(plan.DefaultConnectionSnowflakeID)
+	body.DefaultConnectionSynapseId = formatInt64(plan.DefaultConnectionSynapseID)
+	body.DefaultConnectionExasolId = formatInt64(plan.DefaultConnectionExasolID)
+	body.DefaultConnectionTeradataId = formatInt64(plan.DefaultConnectionTeradataID)
+	body.DefaultFileStorageId = formatInt64(plan.DefaultFileStorageID)
 	if !plan.ZendeskURL.IsNull() {
 		val := plan.ZendeskURL.ValueString()
 		body.ZendeskUrl = &val
@@ -351,3 +350,48 @@ func (r *maintainerResource) ImportState(ctx context.Context, req resource.Impor
 	// Import by ID
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
+
+// UpgradeState migrates the states stored by the previous schema versions.
+func (r *maintainerResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored the IDs of the default connections as strings
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":                              schema.StringAttribute{Computed: true},
+					"name":                            schema.StringAttribute{Required: true},
+					"default_connection_redshift_id":  schema.StringAttribute{Optional: true},
+					"default_connection_snowflake_id": schema.StringAttribute{Optional: true},
+					"default_connection_synapse_id":   schema.StringAttribute{Optional: true},
+					"default_connection_exasol_id":    schema.StringAttribute{Optional: true},
+					"default_connection_teradata_id":  schema.StringAttribute{Optional: true},
+					"default_file_storage_id":         schema.StringAttribute{Optional: true},
+					"zendesk_url":                     schema.StringAttribute{Optional: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior maintainerResourceModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				state := maintainerResourceModel{
+					ID:                           prior.ID,
+					Name:                         prior.Name,
+					DefaultConnectionRedshiftID:  upgradeInt64(prior.DefaultConnectionRedshiftID, "default_connection_redshift_id", &resp.Diagnostics),
+					DefaultConnectionSnowflakeID: upgradeInt64(prior.DefaultConnectionSnowflakeID, "default_connection_snowflake_id", &resp.Diagnostics),
+					DefaultConnectionSynapseID:   upgradeInt64(prior.DefaultConnectionSynapseID, "default_connection_synapse_id", &resp.Diagnostics),
+					DefaultConnectionExasolID:    upgradeInt64(prior.DefaultConnectionExasolID, "default_connection_exasol_id", &resp.Diagnostics),
+					DefaultConnectionTeradataID:  upgradeInt64(prior.DefaultConnectionTeradataID, "default_connection_teradata_id", &resp.Diagnostics),
+					DefaultFileStorageID:         upgradeInt64(prior.DefaultFileStorageID, "default_file_storage_id", &resp.Diagnostics),
+					ZendeskURL:                   prior.ZendeskURL,
+				}
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+			},
+		},
+	}
+}
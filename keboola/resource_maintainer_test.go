@@ -0,0 +1,68 @@
+package keboola
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccMaintainerResource(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_maintainer.test"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeMaintainers),
+		Steps: []resource.TestStep{
+			// Create and read
+			{
+				Config: fake.providerConfig() + testAccMaintainerConfig("tf-test-maintainer", "https://support.example.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckResourceAttr(name, "name", "tf-test-maintainer"),
+					resource.TestCheckResourceAttr(name, "zendesk_url", "https://support.example.com"),
+					fake.checkField(fakeMaintainers, &id, "zendeskUrl", "https://support.example.com"),
+				),
+			},
+			// Import
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The API does not return the name
+				ImportStateVerifyIgnore: []string{"name"},
+			},
+			// Update
+			{
+				Config: fake.providerConfig() + testAccMaintainerConfig("tf-test-maintainer-renamed", "https://help.example.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "name", "tf-test-maintainer-renamed"),
+					fake.checkField(fakeMaintainers, &id, "name", "tf-test-maintainer-renamed"),
+					fake.checkField(fakeMaintainers, &id, "zendeskUrl", "https://help.example.com"),
+				),
+			},
+			// Drift
+			{
+				PreConfig: func() {
+					fake.update(fakeMaintainers, id, func(data map[string]interface{}) {
+						data["zendeskUrl"] = "https://changed.example.com"
+					})
+				},
+				Config:             fake.providerConfig() + testAccMaintainerConfig("tf-test-maintainer-renamed", "https://help.example.com"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccMaintainerConfig(name, zendeskURL string) string {
+	return fmt.Sprintf(`
+resource "keboola-management_maintainer" "test" {
+  name        = %q
+  zendesk_url = %q
+}
+`, name, zendeskURL)
+}
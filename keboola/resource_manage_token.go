@@ -0,0 +1,318 @@
+package keboola
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/relvacode/iso8601"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ resource.Resource                = &manageTokenResource{}
+	_ resource.ResourceWithConfigure   = &manageTokenResource{}
+	_ resource.ResourceWithImportState = &manageTokenResource{}
+)
+
+// NewManageTokenResource returns a new keboola_manage_token resource.
+func NewManageTokenResource() resource.Resource {
+	return &manageTokenResource{}
+}
+
+// manageTokenResource implements the keboola_manage_token resource.
+type manageTokenResource struct {
+	client *Client
+}
+
+// manageTokenResourceModel maps the resource schema data.
+type manageTokenResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Description types.String `tfsdk:"description"`
+	Scopes      types.Set    `tfsdk:"scopes"`
+	ExpiresIn   types.Int64  `tfsdk:"expires_in"`
+	Token       types.String `tfsdk:"token"`
+	Created     types.String `tfsdk:"created"`
+	Expires     types.String `tfsdk:"expires"`
+}
+
+// manageToken is an application token of the Manage API, the SDK does not cover the application token endpoints.
+// The token value is returned only when the token is created.
+type manageToken struct {
+	ID          json.Number `json:"id"`
+	Description string      `json:"description"`
+	Scopes      []string    `json:"scopes"`
+	Created     string      `json:"created"`
+	Expires     *string     `json:"expires"`
+	IsExpired   bool        `json:"isExpired"`
+	IsDisabled  bool        `json:"isDisabled"`
+	Token       string      `json:"token,omitempty"`
+}
+
+// createManageToken creates an application token of the Manage API.
+func (c *Client) createManageToken(ctx context.Context, body map[string]interface{}) (*manageToken, error) {
+	var result manageToken
+	if err := c.sendManageRequest(ctx, http.MethodPost, "/manage/tokens", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// getManageToken returns the detail of an application token.
+func (c *Client) getManageToken(ctx context.Context, id string) (*manageToken, error) {
+	var result manageToken
+	if err := c.sendManageRequest(ctx, http.MethodGet, "/manage/tokens/"+url.PathEscape(id), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// deleteManageToken deletes an application token.
+func (c *Client) deleteManageToken(ctx context.Context, id string) error {
+	return c.sendManageRequest(ctx, http.MethodDelete, "/manage/tokens/"+url.PathEscape(id), nil, nil)
+}
+
+// isManageTokenGone checks whether the token no longer exists, e.g., it was deleted outside of Terraform.
+func isManageTokenGone(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *manageTokenResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.client = req.ProviderData.(*Client)
+}
+
+// Metadata returns the resource type name.
+func (r *manageTokenResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_manage_token"
+}
+
+// Schema defines the schema for the resource.
+func (r *manageTokenResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an application token of the Keboola Management API. " +
+			"The token value is available only when the token is created, any change replaces the token.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Token ID.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Description: "Token description.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"scopes": schema.SetAttribute{
+				Description: "Scopes granted to the token, e.g., `organizations:read` or `projects:write`.",
+				Optional:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+			},
+			"expires_in": schema.Int64Attribute{
+				Description: "Token lifetime in seconds, the token never expires if not set.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"token": schema.StringAttribute{
+				Description: "Token value, empty after import.",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created": schema.StringAttribute{
+				Description: "Time the token was created, in RFC 3339 format.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"expires": schema.StringAttribute{
+				Description: "Time the token expires, in RFC 3339 format, null if the token never expires.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// Create creates the token and stores its value, which cannot be read later.
+func (r *manageTokenResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan manageTokenResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	scopes := []string{}
+	if !plan.Scopes.IsNull() {
+		resp.Diagnostics.Append(plan.Scopes.ElementsAs(ctx, &scopes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	body := map[string]interface{}{
+		"description": plan.Description.ValueString(),
+		"scopes":      scopes,
+	}
+	if !plan.ExpiresIn.IsNull() {
+		body["expiresIn"] = plan.ExpiresIn.ValueInt64()
+	}
+
+	token, err := r.client.createManageToken(ctx, body)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating manage token", "Could not create manage token: "+err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(token.ID.String())
+	plan.Token = types.StringValue(token.Token)
+	if _, err := plan.setTimes(token); err != nil {
+		resp.Diagnostics.AddError("Error reading manage token", err.Error())
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// setTimes sets the creation and expiration time of the token and returns the expiration time,
+// the zero time if the token does not expire.
+func (m *manageTokenResourceModel) setTimes(token *manageToken) (time.Time, error) {
+	created, err := iso8601.ParseString(token.Created)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not parse the token creation time: %w", err)
+	}
+	m.Created = types.StringValue(formatTokenTime(created))
+	m.Expires = types.StringNull()
+	if token.Expires == nil {
+		return time.Time{}, nil
+	}
+	expires, err := iso8601.ParseString(*token.Expires)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not parse the token expiration time: %w", err)
+	}
+	m.Expires = types.StringValue(formatTokenTime(expires))
+	return expires, nil
+}
+
+// Read refreshes the token, a deleted, disabled or expired token is removed from state.
+func (r *manageTokenResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state manageTokenResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	token, err := r.client.getManageToken(ctx, state.ID.ValueString())
+	if isManageTokenGone(err) {
+		tflog.Info(ctx, fmt.Sprintf("Manage token %s no longer exists, removing it from state", state.ID.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading manage token", "Could not read manage token: "+err.Error())
+		return
+	}
+	expires, err := state.setTimes(token)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading manage token", err.Error())
+		return
+	}
+	if token.IsExpired || token.IsDisabled || (!expires.IsZero() && !expires.After(time.Now())) {
+		tflog.Info(ctx, fmt.Sprintf("Manage token %s is expired or disabled, removing it from state", state.ID.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Description = types.StringValue(token.Description)
+	// An empty list of scopes is equal to unset scopes
+	if len(token.Scopes) > 0 || !state.Scopes.IsNull() {
+		scopes, d := types.SetValueFrom(ctx, types.StringType, token.Scopes)
+		resp.Diagnostics.Append(d...)
+		state.Scopes = scopes
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update only stores the plan, all token attributes require replacement.
+func (r *manageTokenResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan manageTokenResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the token, a token which no longer exists is skipped.
+func (r *manageTokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state manageTokenResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.deleteManageToken(ctx, state.ID.ValueString())
+	if isManageTokenGone(err) {
+		tflog.Info(ctx, fmt.Sprintf("Manage token %s no longer exists, skipping deletion", state.ID.ValueString()))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting manage token", "Could not delete manage token: "+err.Error())
+		return
+	}
+}
+
+// ImportState imports an existing resource into Terraform.
+func (r *manageTokenResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import by token ID, the token value is not available after creation
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resp.Diagnostics.AddWarning(
+		"Token value not imported",
+		"The token value can be read only when the token is created, so the token attribute stays empty after import. "+
+			"Replace the resource to get the token value.",
+	)
+}
@@ -0,0 +1,117 @@
+package keboola
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccManageTokenResource(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_manage_token.test"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeManageTokens),
+		Steps: []resource.TestStep{
+			// Create and read
+			{
+				Config: fake.providerConfig() + testAccManageTokenConfig("tf-test-manage-token", `["organizations:read", "projects:write"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckResourceAttr(name, "description", "tf-test-manage-token"),
+					resource.TestCheckResourceAttr(name, "scopes.#", "2"),
+					resource.TestCheckTypeSetElemAttr(name, "scopes.*", "projects:write"),
+					resource.TestCheckResourceAttrSet(name, "token"),
+					resource.TestCheckResourceAttrSet(name, "created"),
+					resource.TestCheckResourceAttrSet(name, "expires"),
+					fake.checkField(fakeManageTokens, &id, "scopes", []string{"organizations:read", "projects:write"}),
+				),
+			},
+			// Import, the token value and the lifetime cannot be read back
+			{
+				ResourceName:            name,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"token", "expires_in"},
+			},
+			// Changed scopes replace the token
+			{
+				Config: fake.providerConfig() + testAccManageTokenConfig("tf-test-manage-token", `["organizations:read"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckNewID(name, &id),
+					resource.TestCheckResourceAttr(name, "scopes.#", "1"),
+					testAccCheckManageTokenCount(fake, 1),
+				),
+			},
+			// A deleted token is removed from state and created again
+			{
+				PreConfig: func() {
+					fake.remove(fakeManageTokens, id)
+				},
+				Config: fake.providerConfig() + testAccManageTokenConfig("tf-test-manage-token", `["organizations:read"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckNewID(name, &id),
+					testAccCheckManageTokenCount(fake, 1),
+				),
+			},
+			// A token past its expiration is replaced, even if the API does not flag it as expired yet
+			{
+				PreConfig: func() {
+					fake.update(fakeManageTokens, id, func(data map[string]interface{}) {
+						data["expires"] = time.Now().Add(-time.Minute).In(time.FixedZone("CEST", 2*60*60)).Format(time.RFC3339)
+					})
+				},
+				Config:             fake.providerConfig() + testAccManageTokenConfig("tf-test-manage-token", `["organizations:read"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// An expired token is replaced
+			{
+				PreConfig: func() {
+					fake.update(fakeManageTokens, id, func(data map[string]interface{}) {
+						data["isExpired"] = true
+					})
+				},
+				Config:             fake.providerConfig() + testAccManageTokenConfig("tf-test-manage-token", `["organizations:read"]`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// testAccCheckNewID verifies that the resource was replaced and captures the new ID.
+func testAccCheckNewID(name string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		newID := s.RootModule().Resources[name].Primary.ID
+		if newID == *id {
+			return fmt.Errorf("expected %s to be replaced, got the same ID %s", name, newID)
+		}
+		*id = newID
+		return nil
+	}
+}
+
+func testAccCheckManageTokenCount(fake *fakeAPI, expected int) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		if n := fake.count(fakeManageTokens); n != expected {
+			return fmt.Errorf("expected %d manage tokens in the fake API, got %d", expected, n)
+		}
+		return nil
+	}
+}
+
+func testAccManageTokenConfig(description, scopes string) string {
+	return fmt.Sprintf(`
+resource "keboola-management_manage_token" "test" {
+  description = %q
+  scopes      = %s
+  expires_in  = 3600
+}
+`, description, scopes)
+}
@@ -2,23 +2,25 @@ package keboola
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
 )
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ resource.Resource                = &organizationResource{}
-	_ resource.ResourceWithConfigure   = &organizationResource{}
-	_ resource.ResourceWithImportState = &organizationResource{}
+	_ resource.Resource                 = &organizationResource{}
+	_ resource.ResourceWithConfigure    = &organizationResource{}
+	_ resource.ResourceWithImportState  = &organizationResource{}
+	_ resource.ResourceWithUpgradeState = &organizationResource{}
 )
 
 // NewOrganizationResource is a helper function to simplify provider implementation.
@@ -33,6 +35,17 @@ type organizationResource struct {
 
 // organizationResourceModel maps the resource schema data.
 type organizationResourceModel struct {
+	ID                      types.String `tfsdk:"id"`
+	Name                    types.String `tfsdk:"name"`
+	MaintainerID            types.Int64  `tfsdk:"maintainer_id"`
+	AllowAutoJoin           types.Bool   `tfsdk:"allow_auto_join"`
+	CrmID                   types.String `tfsdk:"crm_id"`
+	ActivityCenterProjectID types.Int64  `tfsdk:"activity_center_project_id"`
+	MfaRequired             types.Bool   `tfsdk:"mfa_required"`
+}
+
+// organizationResourceModelV0 maps the schema version 0, which stored the numbers and booleans as strings.
+type organizationResourceModelV0 struct {
 	ID                      types.String `tfsdk:"id"`
 	Name                    types.String `tfsdk:"name"`
 	MaintainerID            types.String `tfsdk:"maintainer_id"`
@@ -59,6 +72,7 @@ func (r *organizationResource) Metadata(_ context.Context, req resource.Metadata
 func (r *organizationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages a Keboola organization.",
+		Version:     1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "Organization ID.",
@@ -71,11 +85,14 @@ func (r *organizationResource) Schema(_ context.Context, _ resource.SchemaReques
 				Description: "Organization name.",
 				Required:    true,
 			},
-			"maintainer_id": schema.StringAttribute{
+			"maintainer_id": schema.Int64Attribute{
 				Description: "Assign the organization to another maintainer.",
 				Required:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
-			"allow_auto_join": schema.StringAttribute{
+			"allow_auto_join": schema.BoolAttribute{
 				Description: "Set whether superAdmins need approval to join the organization's projects (default true).",
 				Optional:    true,
 			},
@@ -83,11 +100,14 @@ func (r *organizationResource) Schema(_ context.Context, _ resource.SchemaReques
 				Description: "Set CRM ID. Only maintainer members and superadmins can change this.",
 				Optional:    true,
 			},
-			"activity_center_project_id": schema.StringAttribute{
+			"activity_center_project_id": schema.Int64Attribute{
 				Description: "Set ActivityCenter ProjectId. Only maintainer members and superadmins can change this.",
 				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
-			"mfa_required": schema.StringAttribute{
+			"mfa_required": schema.BoolAttribute{
 				Description: "Toggle whether all members of or organization and its projects must have enabled multi-factor authentication (default false).",
 				Optional:    true,
 			},
@@ -105,16 +125,6 @@ func (r *organizationResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	// Parse maintainer_id for API call
-	maintainerID, err := strconv.Atoi(plan.MaintainerID.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error converting maintainer_id",
-			"Could not convert maintainer_id to integer: "+err.Error(),
-		)
-		return
-	}
-
 	// Build API request body
 	name := plan.Name.ValueString()
 	body := management.CreateAnOrganizationRequest{
@@ -126,7 +136,7 @@ func (r *organizationResource) Create(ctx context.Context, req resource.CreateRe
 	}
 
 	// Create new organization under the specified maintainer
-	apiResp, _, err := r.client.API.OrganizationsAPI.CreateAnOrganization(ctx, float32(maintainerID)).CreateAnOrganizationRequest(body).Execute()
+	apiResp, _, err := r.client.API.OrganizationsAPI.CreateAnOrganization(ctx, float32(plan.MaintainerID.ValueInt64())).CreateAnOrganizationRequest(body).Execute()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating organization",
@@ -144,7 +154,7 @@ func (r *organizationResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	plan.ID = types.StringValue(fmt.Sprintf("%v", int(*apiResp.Id)))
+	plan.ID = types.StringValue(strconv.FormatInt(int64(*apiResp.Id), 10))
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -190,7 +200,7 @@ func (r *organizationResource) Read(ctx context.Context, req resource.ReadReques
 	}
 
 	// Overwrite items with refreshed state
-	state.ID = types.StringValue(fmt.Sprintf("%v", int(*apiResp.Id)))
+	state.ID = types.StringValue(strconv.FormatInt(int64(*apiResp.Id), 10))
 	if apiResp.Name != nil {
 		state.Name = types.StringValue(*apiResp.Name)
 	}
@@ -230,26 +240,14 @@ func (r *organizationResource) Update(ctx context.Context, req resource.UpdateRe
 		name := plan.Name.ValueString()
 		body.Name = &name
 	}
-	if !plan.MaintainerID.IsNull() && plan.MaintainerID.ValueString() != "" {
-		maintainerID := plan.MaintainerID.ValueString()
-		body.MaintainerId = &maintainerID
-	}
-	if !plan.AllowAutoJoin.IsNull() && plan.AllowAutoJoin.ValueString() != "" {
-		allowAutoJoin := plan.AllowAutoJoin.ValueString()
-		body.AllowAutoJoin = &allowAutoJoin
-	}
+	body.MaintainerId = formatInt64(plan.MaintainerID)
+	body.AllowAutoJoin = formatBool(plan.AllowAutoJoin)
 	if !plan.CrmID.IsNull() && plan.CrmID.ValueString() != "" {
 		crmID := plan.CrmID.ValueString()
 		body.CrmId = &crmID
 	}
-	if !plan.ActivityCenterProjectID.IsNull() && plan.ActivityCenterProjectID.ValueString() != "" {
-		acpID := plan.ActivityCenterProjectID.ValueString()
-		body.ActivityCenterProjectId = &acpID
-	}
-	if !plan.MfaRequired.IsNull() && plan.MfaRequired.ValueString() != "" {
-		mfaRequired := plan.MfaRequired.ValueString()
-		body.MfaRequired = &mfaRequired
-	}
+	body.ActivityCenterProjectId = formatInt64(plan.ActivityCenterProjectID)
+	body.MfaRequired = formatBool(plan.MfaRequired)
 
 	// Update existing organization
 	_, _, err = r.client.API.OrganizationsAPI.UpdateAnOrganization(ctx, orgID).UpdateAnOrganizationRequest(body).Execute()
@@ -305,3 +303,44 @@ func (r *organizationResource) ImportState(ctx context.Context, req resource.Imp
 	// Import by ID
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
+
+// UpgradeState migrates the states stored by the previous schema versions.
+func (r *organizationResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored the numbers and booleans as strings
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":                         schema.StringAttribute{Computed: true},
+					"name":                       schema.StringAttribute{Required: true},
+					"maintainer_id":              schema.StringAttribute{Required: true},
+					"allow_auto_join":            schema.StringAttribute{Optional: true},
+					"crm_id":                     schema.StringAttribute{Optional: true},
+					"activity_center_project_id": schema.StringAttribute{Optional: true},
+					"mfa_required":               schema.StringAttribute{Optional: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior organizationResourceModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				state := organizationResourceModel{
+					ID:                      prior.ID,
+					Name:                    prior.Name,
+					MaintainerID:            upgradeInt64(prior.MaintainerID, "maintainer_id", &resp.Diagnostics),
+					AllowAutoJoin:           upgradeBool(prior.AllowAutoJoin, "allow_auto_join", &resp.Diagnostics),
+					CrmID:                   prior.CrmID,
+					ActivityCenterProjectID: upgradeInt64(prior.ActivityCenterProjectID, "activity_center_project_id", &resp.Diagnostics),
+					MfaRequired:             upgradeBool(prior.MfaRequired, "mfa_required", &resp.Diagnostics),
+				}
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+			},
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package keboola
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccOrganizationResource(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_organization.test"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeOrganizations, fakeMaintainers),
+		Steps: []resource.TestStep{
+			// Create and read
+			{
+				Config: fake.providerConfig() + testAccOrganizationConfig("tf-test-organization"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckResourceAttr(name, "name", "tf-test-organization"),
+					resource.TestCheckResourceAttrPair(name, "maintainer_id", "keboola-management_maintainer.test", "id"),
+					fake.checkField(fakeOrganizations, &id, "crmId", "crm-1"),
+				),
+			},
+			// Import
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The API does not return the maintainer and the CRM ID is not read back
+				ImportStateVerifyIgnore: []string{"maintainer_id", "crm_id"},
+			},
+			// Update
+			{
+				Config: fake.providerConfig() + testAccOrganizationConfig("tf-test-organization-renamed"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "name", "tf-test-organization-renamed"),
+					fake.checkField(fakeOrganizations, &id, "name", "tf-test-organization-renamed"),
+				),
+			},
+			// Drift
+			{
+				PreConfig: func() {
+					fake.update(fakeOrganizations, id, func(data map[string]interface{}) {
+						data["name"] = "changed outside of Terraform"
+					})
+				},
+				Config:             fake.providerConfig() + testAccOrganizationConfig("tf-test-organization-renamed"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccOrganizationConfig(name string) string {
+	return testAccMaintainerConfig("tf-test-maintainer", "https://support.example.com") + fmt.Sprintf(`
+resource "keboola-management_organization" "test" {
+  name          = %q
+  maintainer_id = keboola-management_maintainer.test.id
+  crm_id        = "crm-1"
+}
+`, name)
+}
@@ -2,22 +2,26 @@ package keboola
 
 import (
 	"context"
-	"fmt"
+	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
 )
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ resource.Resource                = &projectResource{}
-	_ resource.ResourceWithConfigure   = &projectResource{}
-	_ resource.ResourceWithImportState = &projectResource{}
+	_ resource.Resource                 = &projectResource{}
+	_ resource.ResourceWithConfigure    = &projectResource{}
+	_ resource.ResourceWithImportState  = &projectResource{}
+	_ resource.ResourceWithUpgradeState = &projectResource{}
 )
 
 // NewProjectResource is a helper function to simplify provider implementation.
@@ -32,6 +36,16 @@ type projectResource struct {
 
 // projectResourceModel maps the resource schema data.
 type projectResourceModel struct {
+	ID                      types.String `tfsdk:"id"`
+	Name                    types.String `tfsdk:"name"`
+	OrganizationID          types.Int64  `tfsdk:"organization_id"`
+	Type                    types.String `tfsdk:"type"`
+	DefaultBackend          types.String `tfsdk:"default_backend"`
+	DataRetentionTimeInDays types.Int64  `tfsdk:"data_retention_time_in_days"`
+}
+
+// projectResourceModelV0 maps the schema version 0, which stored the numbers as strings.
+type projectResourceModelV0 struct {
 	ID                      types.String `tfsdk:"id"`
 	Name                    types.String `tfsdk:"name"`
 	OrganizationID          types.String `tfsdk:"organization_id"`
@@ -57,6 +71,7 @@ func (r *projectResource) Metadata(_ context.Context, req resource.MetadataReque
 func (r *projectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages a Keboola project.",
+		Version:     1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "Project ID.",
@@ -69,21 +84,33 @@ func (r *projectResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Description: "Project name.",
 				Required:    true,
 			},
-			"organization_id": schema.StringAttribute{
+			"organization_id": schema.Int64Attribute{
 				Description: "ID of the organization to which the project belongs.",
 				Required:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
 			"type": schema.StringAttribute{
 				Description: "Project type: one of production, poc, demo; default is production.",
 				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(projectTypes...),
+				},
 			},
 			"default_backend": schema.StringAttribute{
-				Description: "Project default backend: snowflake or redshift; default is snowflake.",
+				Description: "Project default backend: one of snowflake, redshift, synapse, exasol, teradata, bigquery; default is snowflake.",
 				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(projectDefaultBackends()...),
+				},
 			},
-			"data_retention_time_in_days": schema.StringAttribute{
+			"data_retention_time_in_days": schema.Int64Attribute{
 				Description: "Data retention in days for Time Travel.",
 				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
 			},
 		},
 	}
@@ -110,12 +137,10 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 		backend := plan.DefaultBackend.ValueString()
 		body.DefaultBackend = &backend // pointer to string
 	}
-	if !plan.DataRetentionTimeInDays.IsNull() && plan.DataRetentionTimeInDays.ValueString() != "" {
-		days := plan.DataRetentionTimeInDays.ValueString()
-		body.DataRetentionTimeInDays = &days // pointer to string
-	}
+	body.DataRetentionTimeInDays = formatInt64(plan.DataRetentionTimeInDays)
 
-	apiResp, _, err := r.client.API.ProjectsAPI.AddAProject(ctx, plan.OrganizationID.ValueString()).AddAProjectRequest(body).Execute()
+	organizationID := strconv.FormatInt(plan.OrganizationID.ValueInt64(), 10)
+	apiResp, _, err := r.client.API.ProjectsAPI.AddAProject(ctx, organizationID).AddAProjectRequest(body).Execute()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating project",
@@ -130,7 +155,7 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 		)
 		return
 	}
-	plan.ID = types.StringValue(fmt.Sprintf("%v", *apiResp.Id))
+	plan.ID = types.StringValue(strconv.FormatInt(int64(*apiResp.Id), 10))
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -165,7 +190,7 @@ func (r *projectResource) Read(ctx context.Context, req resource.ReadRequest, re
 	}
 
 	// Overwrite items with refreshed state
-	state.ID = types.StringValue(fmt.Sprintf("%v", *apiResp.Id))
+	state.ID = types.StringValue(strconv.FormatInt(int64(*apiResp.Id), 10))
 	if apiResp.Name != nil {
 		state.Name = types.StringValue(*apiResp.Name)
 	}
@@ -241,3 +266,42 @@ func (r *projectResource) ImportState(ctx context.Context, req resource.ImportSt
 	// Import by ID
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
+
+// UpgradeState migrates the states stored by the previous schema versions.
+func (r *projectResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored the numbers as strings
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":                          schema.StringAttribute{Computed: true},
+					"name":                        schema.StringAttribute{Required: true},
+					"organization_id":             schema.StringAttribute{Required: true},
+					"type":                        schema.StringAttribute{Required: true},
+					"default_backend":             schema.StringAttribute{Optional: true},
+					"data_retention_time_in_days": schema.StringAttribute{Optional: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior projectResourceModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				state := projectResourceModel{
+					ID:                      prior.ID,
+					Name:                    prior.Name,
+					OrganizationID:          upgradeInt64(prior.OrganizationID, "organization_id", &resp.Diagnostics),
+					Type:                    prior.Type,
+					DefaultBackend:          prior.DefaultBackend,
+					DataRetentionTimeInDays: upgradeInt64(prior.DataRetentionTimeInDays, "data_retention_time_in_days", &resp.Diagnostics),
+				}
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+			},
+		},
+	}
+}
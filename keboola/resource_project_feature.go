@@ -61,6 +61,7 @@ func (r *projectFeatureResource) Schema(_ context.Context, _ resource.SchemaRequ
 			"project_id": schema.StringAttribute{
 				Description: "ID of the project.",
 				Required:    true,
+				Validators:  numericIDValidators(),
 			},
 			"feature": schema.StringAttribute{
 				Description: "Feature to add to the project.",
@@ -165,5 +166,9 @@ func (r *projectFeatureResource) Update(ctx context.Context, req resource.Update
 
 func (r *projectFeatureResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Import by ID (project_id:feature)
+	importCompositeID(ctx, req, resp, "project_id:feature", "project_id", "feature")
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
@@ -0,0 +1,87 @@
+package keboola
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccProjectFeatureResource(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_project_feature.test"
+	var projectID string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeProjects),
+		Steps: []resource.TestStep{
+			// Create and read
+			{
+				Config: fake.providerConfig() + testAccProjectFeatureConfig("tf-test-feature"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("keboola-management_project.test", "id", func(value string) error {
+						projectID = value
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith(name, "id", func(value string) error {
+						if value != projectID+":tf-test-feature" {
+							return fmt.Errorf("unexpected ID %q", value)
+						}
+						return nil
+					}),
+					testAccCheckProjectFeatures(fake, &projectID, "tf-test-feature"),
+				),
+			},
+			// Import by project_id:feature
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateIdFunc: testAccCompositeImportID(name, "project_id", "feature"),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:  name,
+				ImportState:   true,
+				ImportStateId: "tf-test-feature",
+				ExpectError:   regexp.MustCompile(`Expected import identifier with format: project_id:feature`),
+			},
+			// Drift: the feature is removed outside of Terraform
+			{
+				PreConfig: func() {
+					fake.update(fakeProjects, projectID, func(data map[string]interface{}) {
+						data["features"] = []interface{}{}
+					})
+				},
+				Config:             fake.providerConfig() + testAccProjectFeatureConfig("tf-test-feature"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// testAccCheckProjectFeatures verifies the features assigned to the project in the fake API.
+func testAccCheckProjectFeatures(fake *fakeAPI, projectID *string, expected ...string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		data, ok := fake.get(fakeProjects, *projectID)
+		if !ok {
+			return fmt.Errorf("project %q not found in the fake API", *projectID)
+		}
+		if actual, want := fmt.Sprintf("%v", data["features"]), fmt.Sprintf("%v", expected); actual != want {
+			return fmt.Errorf("expected project features %s, got %s", want, actual)
+		}
+		return nil
+	}
+}
+
+func testAccProjectFeatureConfig(feature string) string {
+	return testAccProjectConfig("tf-test-project") + fmt.Sprintf(`
+resource "keboola-management_project_feature" "test" {
+  project_id = keboola-management_project.test.id
+  feature    = %q
+}
+`, feature)
+}
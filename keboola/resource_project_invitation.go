@@ -2,24 +2,50 @@ package keboola
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/relvacode/iso8601"
 
 	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
 )
 
+// Statuses of a project invitation.
+const (
+	invitationStatusPending  = "pending"
+	invitationStatusAccepted = "accepted"
+	invitationStatusExpired  = "expired"
+)
+
+// invitationWaitTimeout is the default of wait_for_acceptance_timeout in seconds.
+const invitationWaitTimeout = 1800
+
+// invitationPollInterval is the interval between the checks of wait_for_acceptance, tests shorten it.
+var invitationPollInterval = 10 * time.Second
+
 // Ensure the implementation satisfies the expected interfaces
 var (
 	_ resource.Resource                = &projectInvitationResource{}
 	_ resource.ResourceWithConfigure   = &projectInvitationResource{}
 	_ resource.ResourceWithImportState = &projectInvitationResource{}
+	_ resource.ResourceWithModifyPlan  = &projectInvitationResource{}
 )
 
 // NewProjectInvitationResource is a helper function to simplify provider implementation.
@@ -41,6 +67,10 @@ type projectInvitationResourceModel struct {
 	ExpirationSeconds types.Number `tfsdk:"expiration_seconds"`
 	Reason            types.String `tfsdk:"reason"`
 	Status            types.String `tfsdk:"status"` // Computed field to track invitation status
+
+	WaitForAcceptance        types.Bool   `tfsdk:"wait_for_acceptance"`
+	WaitForAcceptanceTimeout types.Int64  `tfsdk:"wait_for_acceptance_timeout"`
+	ResendTrigger            types.String `tfsdk:"resend_trigger"`
 }
 
 // Configure adds the provider configured client to the resource.
@@ -71,14 +101,20 @@ func (r *projectInvitationResource) Schema(_ context.Context, _ resource.SchemaR
 			"project_id": schema.StringAttribute{
 				Description: "ID of the project to which the invitation is sent.",
 				Required:    true,
+				Validators:  numericIDValidators(),
 			},
 			"email": schema.StringAttribute{
 				Description: "Email address of the invited user.",
 				Required:    true,
+				Validators:  emailValidators(),
 			},
 			"role": schema.StringAttribute{
-				Description: "Role to assign to the invited user.",
-				Required:    true,
+				Description: "Role to assign to the invited user: one of admin, guest, readOnly, share. A change sends a pending or expired invitation again " +
+					"with the new role, the role of an accepted invitation is changed on the project membership.",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(projectRoles...),
+				},
 			},
 			"expiration_seconds": schema.NumberAttribute{
 				Description: "After how many seconds the invitation and membership of a user will expire.",
@@ -89,8 +125,30 @@ func (r *projectInvitationResource) Schema(_ context.Context, _ resource.SchemaR
 				Optional:    true,
 			},
 			"status": schema.StringAttribute{
-				Description: "Status of the invitation (e.g., 'pending', 'accepted', 'expired').",
+				Description: "Status of the invitation, `pending`, `accepted` or `expired`. " +
+					"An invitation is expired when its expiration passed before the user accepted it.",
+				Computed: true,
+			},
+			"wait_for_acceptance": schema.BoolAttribute{
+				Description: "Wait until the user accepts the invitation when it is sent. " +
+					"If the invitation is not accepted in time, the apply fails. A created resource is then tainted, a resent invitation stays pending.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"wait_for_acceptance_timeout": schema.Int64Attribute{
+				Description: "How many seconds to wait for the acceptance, defaults to 1800.",
+				Optional:    true,
 				Computed:    true,
+				Default:     int64default.StaticInt64(invitationWaitTimeout),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"resend_trigger": schema.StringAttribute{
+				Description: "Arbitrary value, a change cancels the pending or expired invitation and sends it again. " +
+					"It has no effect on an accepted invitation.",
+				Optional: true,
 			},
 		},
 	}
@@ -105,6 +163,28 @@ func (r *projectInvitationResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
+	id, err := r.invite(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating project invitation",
+			"Could not create project invitation: "+err.Error(),
+		)
+		return
+	}
+	plan.ID = types.StringValue(id)
+	plan.Status = types.StringValue(invitationStatusPending)
+
+	if plan.WaitForAcceptance.ValueBool() {
+		r.waitForAcceptance(ctx, &plan, "the resource is tainted and replaced on the next apply", &resp.Diagnostics)
+	}
+
+	// Set state to fully populated data, also when the wait failed, so the invitation is tracked
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// invite sends the invitation and returns its ID.
+func (r *projectInvitationResource) invite(ctx context.Context, plan projectInvitationResourceModel) (string, error) {
 	// Build the API request
 	apiReq := management.InviteAUserToAProjectRequest{
 		Email: plan.Email.ValueString(),
@@ -126,25 +206,140 @@ func (r *projectInvitationResource) Create(ctx context.Context, req resource.Cre
 	// Call the API to send the invitation
 	apiResp, _, err := r.client.API.ProjectsAPI.InviteAUserToAProject(ctx, plan.ProjectID.ValueString()).InviteAUserToAProjectRequest(apiReq).Execute()
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating project invitation",
-			"Could not create project invitation: "+err.Error(),
-		)
-		return
+		return "", err
 	}
 	if apiResp == nil || apiResp.Id == nil {
-		resp.Diagnostics.AddError(
-			"Error creating project invitation",
-			"API did not return invitation ID",
-		)
-		return
+		return "", errors.New("API did not return invitation ID")
 	}
-	plan.ID = types.StringValue(fmt.Sprintf("%v", *apiResp.Id))
-	plan.Status = types.StringValue("pending")
+	return strconv.FormatInt(int64(*apiResp.Id), 10), nil
+}
 
-	// Set state to fully populated data
-	diags = resp.State.Set(ctx, plan)
-	resp.Diagnostics.Append(diags...)
+// cancel cancels the invitation, an invitation which no longer exists is skipped.
+func (r *projectInvitationResource) cancel(ctx context.Context, state projectInvitationResourceModel) error {
+	httpResp, err := r.client.API.ProjectsAPI.CancelProjectInvitation(ctx, state.ProjectID.ValueString(), state.ID.ValueString()).Execute()
+	if err != nil && httpResp != nil && httpResp.StatusCode == http.StatusNotFound {
+		// The invitation was already accepted, expired or cancelled
+		tflog.Info(ctx, fmt.Sprintf("Project invitation %s no longer exists, skipping cancellation", state.ID.ValueString()))
+		return nil
+	}
+	return err
+}
+
+// invitationState is the remote state of an invitation.
+type invitationState struct {
+	// status is empty if neither the invitation nor the membership exists
+	status string
+	// invitation is the detail of a pending or expired invitation
+	invitation *management.InviteAUserToAProject201Response
+	// member is the project membership of the user who accepted the invitation
+	member *management.ListProjectUsers200ResponseInner
+}
+
+// invitationStatus returns the remote state of the invitation.
+// An accepted invitation no longer exists, the user is a member of the project instead.
+func (r *projectInvitationResource) invitationStatus(ctx context.Context, state projectInvitationResourceModel) (invitationState, error) {
+	apiResp, httpResp, err := r.client.API.ProjectsAPI.ProjectInvitationDetail(ctx, state.ProjectID.ValueString(), state.ID.ValueString()).Execute()
+	if err != nil && httpResp != nil && httpResp.StatusCode == http.StatusNotFound {
+		member, err := r.client.findProjectMember(ctx, state.ProjectID.ValueString(), state.Email.ValueString())
+		if err != nil {
+			return invitationState{}, err
+		}
+		if member != nil {
+			return invitationState{status: invitationStatusAccepted, member: member}, nil
+		}
+		return invitationState{}, nil
+	}
+	if err != nil {
+		return invitationState{}, err
+	}
+	if apiResp == nil || apiResp.Id == nil {
+		return invitationState{}, nil
+	}
+
+	if apiResp.Expires != nil && *apiResp.Expires != "" {
+		expires, err := iso8601.ParseString(*apiResp.Expires)
+		if err != nil {
+			return invitationState{}, fmt.Errorf("could not parse the invitation expiration: %w", err)
+		}
+		if !expires.After(time.Now()) {
+			return invitationState{status: invitationStatusExpired, invitation: apiResp}, nil
+		}
+	}
+	return invitationState{status: invitationStatusPending, invitation: apiResp}, nil
+}
+
+// findProjectMember returns the project member with the email, or nil if the user is not a member.
+func (c *Client) findProjectMember(ctx context.Context, projectID, email string) (*management.ListProjectUsers200ResponseInner, error) {
+	users, _, err := c.API.ProjectsAPI.ListProjectUsers(ctx, projectID).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("could not list users of project %s: %w", projectID, err)
+	}
+	for i := range users {
+		if strings.EqualFold(users[i].Email, email) {
+			return &users[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// changeMemberRole changes the role of the user who accepted the invitation.
+func (r *projectInvitationResource) changeMemberRole(ctx context.Context, plan projectInvitationResourceModel) error {
+	member, err := r.client.findProjectMember(ctx, plan.ProjectID.ValueString(), plan.Email.ValueString())
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return fmt.Errorf("%s is not a member of project %s", plan.Email.ValueString(), plan.ProjectID.ValueString())
+	}
+
+	role := plan.Role.ValueString()
+	userID := strconv.FormatInt(int64(member.Id), 10)
+	_, _, err = r.client.API.ProjectsAPI.ChangeRoleOfAUserInAProject(ctx, plan.ProjectID.ValueString(), userID).
+		ChangeRoleOfAUserInAProjectRequest(management.ChangeRoleOfAUserInAProjectRequest{Role: &role}).Execute()
+	return err
+}
+
+// waitForAcceptance polls the invitation until the user accepts it and updates the status.
+// It fails when the invitation expires or is cancelled, or when the timeout passes,
+// timeoutHint then tells the user what happens to the pending invitation.
+func (r *projectInvitationResource) waitForAcceptance(ctx context.Context, model *projectInvitationResourceModel, timeoutHint string, diags *diag.Diagnostics) {
+	timeout := time.Duration(model.WaitForAcceptanceTimeout.ValueInt64()) * time.Second
+	deadline := time.Now().Add(timeout)
+	tflog.Info(ctx, fmt.Sprintf("Waiting up to %s for %s to accept project invitation %s", timeout, model.Email.ValueString(), model.ID.ValueString()))
+
+	for {
+		remote, err := r.invitationStatus(ctx, *model)
+		if err != nil {
+			diags.AddError("Error waiting for project invitation acceptance", "Could not read invitation: "+err.Error())
+			return
+		}
+		switch status := remote.status; status {
+		case invitationStatusAccepted:
+			model.Status = types.StringValue(status)
+			return
+		case invitationStatusExpired:
+			model.Status = types.StringValue(status)
+			diags.AddError("Project invitation expired", fmt.Sprintf("The invitation of %s expired before it was accepted.", model.Email.ValueString()))
+			return
+		case "":
+			diags.AddError("Project invitation cancelled", fmt.Sprintf("The invitation of %s was cancelled or declined before it was accepted.", model.Email.ValueString()))
+			return
+		}
+
+		if !time.Now().Add(invitationPollInterval).Before(deadline) {
+			diags.AddError(
+				"Project invitation not accepted",
+				fmt.Sprintf("%s did not accept the invitation within %s. The invitation stays pending, %s.", model.Email.ValueString(), timeout, timeoutHint),
+			)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			diags.AddError("Error waiting for project invitation acceptance", ctx.Err().Error())
+			return
+		case <-time.After(invitationPollInterval):
+		}
+	}
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -156,77 +351,153 @@ func (r *projectInvitationResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
-	// Call the API to get invitation details
-	apiResp, _, err := r.client.API.ProjectsAPI.ProjectInvitationDetail(ctx, state.ProjectID.ValueString(), state.ID.ValueString()).Execute()
+	remote, err := r.invitationStatus(ctx, state)
 	if err != nil {
-		// Check if the error is a 404 (invitation not found)
-		// This can happen when the invitation was accepted or expired
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Not Found") {
-			// Mark the invitation as accepted and keep it in state
-			// This prevents Terraform from trying to recreate the invitation
-			state.Status = types.StringValue("accepted")
-			diags = resp.State.Set(ctx, &state)
-			resp.Diagnostics.Append(diags...)
-			return
-		}
-
 		resp.Diagnostics.AddError(
 			"Error reading project invitation",
 			"Could not read invitation: "+err.Error(),
 		)
 		return
 	}
-	if apiResp == nil || apiResp.Id == nil {
+	if remote.status == "" {
+		// The invitation was cancelled or declined, it is sent again
+		tflog.Info(ctx, fmt.Sprintf("Project invitation %s no longer exists and the user is not a project member, removing it from state", state.ID.ValueString()))
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	state.Status = types.StringValue(remote.status)
 
-	// Overwrite items with refreshed state
-	state.ID = types.StringValue(fmt.Sprintf("%v", *apiResp.Id))
-	if apiResp.User != nil && apiResp.User.Email != nil {
-		state.Email = types.StringValue(*apiResp.User.Email)
-	}
-	if apiResp.Role != nil {
-		state.Role = types.StringValue(*apiResp.Role)
+	if apiResp := remote.invitation; apiResp != nil {
+		// Overwrite items with refreshed state
+		state.ID = types.StringValue(strconv.FormatInt(int64(*apiResp.Id), 10))
+		if apiResp.User != nil && apiResp.User.Email != nil {
+			state.Email = types.StringValue(*apiResp.User.Email)
+		}
+		if apiResp.Role != nil {
+			state.Role = types.StringValue(*apiResp.Role)
+		}
+		if apiResp.Reason != nil {
+			state.Reason = types.StringValue(*apiResp.Reason)
+		}
+		// ExpirationSeconds is not directly available, so leave as is (API may provide Expires as a timestamp)
 	}
-	if apiResp.Reason != nil {
-		state.Reason = types.StringValue(*apiResp.Reason)
+	if remote.member != nil {
+		// The role of an accepted invitation is the role of the membership
+		state.Role = types.StringValue(remote.member.Role)
 	}
-	// Set status to pending if the invitation exists
-	state.Status = types.StringValue("pending")
-	// ExpirationSeconds is not directly available, so leave as is (API may provide Expires as a timestamp)
-	// If needed, parse apiResp.Expires and apiResp.Created to calculate seconds
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 }
 
+// ModifyPlan describes how the changes of a sent invitation are applied.
+// A pending or expired invitation is cancelled and sent again with a new ID,
+// the role of an accepted invitation is changed on the project membership.
+func (r *projectInvitationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Skip on create and destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state projectInvitationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || plan.ResendTrigger.IsUnknown() || plan.Role.IsUnknown() {
+		return
+	}
+	roleChanged := !plan.Role.Equal(state.Role)
+	resend := !plan.ResendTrigger.Equal(state.ResendTrigger)
+	if !roleChanged && !resend {
+		return
+	}
+
+	if state.Status.ValueString() == invitationStatusAccepted {
+		if roleChanged {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("role"),
+				"Project membership role will be changed",
+				fmt.Sprintf("The user %s already accepted the invitation, the role of the project membership will be changed from %q to %q.",
+					state.Email.ValueString(), state.Role.ValueString(), plan.Role.ValueString()),
+			)
+		}
+		if resend {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("resend_trigger"),
+				"Invitation already accepted",
+				"The user already accepted the invitation, changing resend_trigger does not send it again.",
+			)
+		}
+		return
+	}
+
+	attr, reason := path.Root("resend_trigger"), "resend_trigger changed"
+	if roleChanged {
+		attr, reason = path.Root("role"), fmt.Sprintf("the role changed from %q to %q", state.Role.ValueString(), plan.Role.ValueString())
+	}
+	resp.Diagnostics.AddAttributeWarning(
+		attr,
+		"Project invitation will be sent again",
+		fmt.Sprintf("The %s invitation of %s will be cancelled and sent again, because %s.", state.Status.ValueString(), state.Email.ValueString(), reason),
+	)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+}
+
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *projectInvitationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Get the current state to check if the invitation was accepted
-	var state projectInvitationResourceModel
-	diags := req.State.Get(ctx, &state)
-	resp.Diagnostics.Append(diags...)
+	var plan, state projectInvitationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	plan.ID = state.ID
+	plan.Status = state.Status
 
-	// If the invitation was accepted, don't allow updates
-	if !state.Status.IsNull() && state.Status.ValueString() == "accepted" {
-		resp.Diagnostics.AddWarning(
-			"Cannot update accepted invitation",
-			"The invitation was accepted and can no longer be modified.",
-		)
-		// Keep the existing state
-		diags = resp.State.Set(ctx, &state)
+	if state.Status.ValueString() == invitationStatusAccepted {
+		// The invitation was accepted, only the role of the membership can be changed
+		if !plan.Role.Equal(state.Role) {
+			if err := r.changeMemberRole(ctx, plan); err != nil {
+				resp.Diagnostics.AddError(
+					"Error updating project membership",
+					"Could not change the role of the project member: "+err.Error(),
+				)
+				return
+			}
+		}
+		diags := resp.State.Set(ctx, &plan)
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	// Project invitations cannot be updated. This method is required by the interface.
-	// For now, we'll just keep the existing state
-	diags = resp.State.Set(ctx, &state)
+	// A changed role or resend_trigger cancels the invitation and sends it again
+	if !plan.Role.Equal(state.Role) || !plan.ResendTrigger.Equal(state.ResendTrigger) {
+		if err := r.cancel(ctx, state); err != nil {
+			resp.Diagnostics.AddError(
+				"Error resending project invitation",
+				"Could not cancel invitation: "+err.Error(),
+			)
+			return
+		}
+		id, err := r.invite(ctx, plan)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error resending project invitation",
+				"Could not create project invitation: "+err.Error(),
+			)
+			// The cancelled invitation no longer exists
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		plan.ID = types.StringValue(id)
+		plan.Status = types.StringValue(invitationStatusPending)
+	}
+
+	if plan.WaitForAcceptance.ValueBool() && plan.Status.ValueString() == invitationStatusPending {
+		r.waitForAcceptance(ctx, &plan, "change resend_trigger to send it again", &resp.Diagnostics)
+	}
+
+	diags := resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 }
 
@@ -239,16 +510,7 @@ func (r *projectInvitationResource) Delete(ctx context.Context, req resource.Del
 		return
 	}
 
-	_, err := r.client.API.ProjectsAPI.CancelProjectInvitation(ctx, state.ProjectID.ValueString(), state.ID.ValueString()).Execute()
-	if err != nil {
-		// Check if the error is a 404 (invitation not found)
-		// This can happen when the invitation was already accepted or expired
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Not Found") {
-			// The invitation no longer exists, which is fine for deletion
-			// Just log this as info and continue
-			return
-		}
-
+	if err := r.cancel(ctx, state); err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting project invitation",
 			"Could not delete invitation: "+err.Error(),
@@ -259,6 +521,12 @@ func (r *projectInvitationResource) Delete(ctx context.Context, req resource.Del
 
 // ImportState imports an existing resource into Terraform.
 func (r *projectInvitationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import by ID
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Import by project_id:invitation_id, the invitation can be read only within its project
+	importCompositeID(ctx, req, resp, "project_id:invitation_id", "project_id", "id")
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// Defaults are not applied on import
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("wait_for_acceptance"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("wait_for_acceptance_timeout"), int64(invitationWaitTimeout))...)
 }
@@ -0,0 +1,360 @@
+package keboola
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccProjectInvitationResource(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_project_invitation.test"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeInvitations, fakeProjects),
+		Steps: []resource.TestStep{
+			// Create and read
+			{
+				Config: fake.providerConfig() + testAccProjectInvitationConfig("guest"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckResourceAttr(name, "email", "tf-test-user@example.com"),
+					resource.TestCheckResourceAttr(name, "role", "guest"),
+					resource.TestCheckResourceAttr(name, "status", "pending"),
+					fake.checkField(fakeInvitations, &id, "role", "guest"),
+				),
+			},
+			// Import by project_id:invitation_id
+			{
+				ResourceName:            name,
+				ImportState:             true,
+				ImportStateIdFunc:       testAccCompositeImportID(name, "project_id", "id"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"expiration_seconds"},
+			},
+			{
+				ResourceName:  name,
+				ImportState:   true,
+				ImportStateId: "invitation-without-project",
+				ExpectError:   regexp.MustCompile(`Expected import identifier with format: project_id:invitation_id`),
+			},
+			// Drift
+			{
+				PreConfig: func() {
+					fake.update(fakeInvitations, id, func(data map[string]interface{}) {
+						data["role"] = "admin"
+					})
+				},
+				Config:             fake.providerConfig() + testAccProjectInvitationConfig("guest"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccProjectInvitationResource_resend(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_project_invitation.test"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeInvitations, fakeProjects),
+		Steps: []resource.TestStep{
+			// Create
+			{
+				Config: fake.providerConfig() + testAccProjectInvitationResendConfig("1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckResourceAttr(name, "status", "pending"),
+				),
+			},
+			// The status of an invitation which was not accepted in time is expired
+			{
+				PreConfig: func() {
+					fake.update(fakeInvitations, id, func(data map[string]interface{}) {
+						data["expires"] = fakeTimestamp(time.Now().Add(-time.Hour))
+					})
+				},
+				Config: fake.providerConfig() + testAccProjectInvitationResendConfig("1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", id),
+					resource.TestCheckResourceAttr(name, "status", "expired"),
+				),
+			},
+			// A changed resend_trigger cancels the invitation and sends it again
+			{
+				Config: fake.providerConfig() + testAccProjectInvitationResendConfig("2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckNewID(name, &id),
+					resource.TestCheckResourceAttr(name, "status", "pending"),
+					resource.TestCheckResourceAttr(name, "resend_trigger", "2"),
+					testAccCheckInvitationCount(fake, 1),
+				),
+			},
+			// A cancelled invitation of a user who is not a member is sent again
+			{
+				PreConfig: func() {
+					fake.remove(fakeInvitations, id)
+				},
+				Config: fake.providerConfig() + testAccProjectInvitationResendConfig("2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckNewID(name, &id),
+					testAccCheckInvitationCount(fake, 1),
+				),
+			},
+			// An accepted invitation is kept, resend_trigger has no effect
+			{
+				PreConfig: func() {
+					fake.acceptInvitations()
+				},
+				Config: fake.providerConfig() + testAccProjectInvitationResendConfig("3"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", id),
+					resource.TestCheckResourceAttr(name, "status", "accepted"),
+					testAccCheckInvitationCount(fake, 0),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProjectInvitationResource_role(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_project_invitation.test"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeInvitations, fakeProjects),
+		Steps: []resource.TestStep{
+			// Create
+			{
+				Config: fake.providerConfig() + testAccProjectInvitationConfig("guest"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckResourceAttr(name, "role", "guest"),
+				),
+			},
+			// A changed role cancels the pending invitation and sends it again
+			{
+				Config: fake.providerConfig() + testAccProjectInvitationConfig("admin"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckNewID(name, &id),
+					resource.TestCheckResourceAttr(name, "role", "admin"),
+					resource.TestCheckResourceAttr(name, "status", "pending"),
+					fake.checkField(fakeInvitations, &id, "role", "admin"),
+					testAccCheckInvitationCount(fake, 1),
+				),
+			},
+			// A changed role of an accepted invitation changes the role of the membership
+			{
+				PreConfig: func() {
+					fake.acceptInvitations()
+				},
+				Config: fake.providerConfig() + testAccProjectInvitationConfig("guest"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", id),
+					resource.TestCheckResourceAttr(name, "role", "guest"),
+					resource.TestCheckResourceAttr(name, "status", "accepted"),
+					testAccCheckInvitationCount(fake, 0),
+					testAccCheckProjectMemberRole(fake, "guest"),
+				),
+			},
+			// A role changed out of band is detected as drift
+			{
+				PreConfig: func() {
+					for _, member := range fake.list(fakeProjectUsers, "") {
+						fake.update(fakeProjectUsers, stringValue(member.(map[string]interface{})["id"]), func(data map[string]interface{}) {
+							data["role"] = "admin"
+						})
+					}
+				},
+				Config:             fake.providerConfig() + testAccProjectInvitationConfig("guest"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccProjectInvitationResource_validation(t *testing.T) {
+	fake := newFakeAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fake.providerConfig() + testAccProjectInvitationAttributesConfig("1", "tf-test-user@example.com", "owner"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			{
+				Config:      fake.providerConfig() + testAccProjectInvitationAttributesConfig("1", "tf-test-user", "guest"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must be an email address`),
+			},
+			{
+				Config:      fake.providerConfig() + testAccProjectInvitationAttributesConfig("my-project", "tf-test-user@example.com", "guest"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must be a numeric ID`),
+			},
+		},
+	})
+}
+
+func TestAccProjectInvitationResource_waitForAcceptance(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_project_invitation.test"
+	pollInterval := invitationPollInterval
+	invitationPollInterval = 100 * time.Millisecond
+	t.Cleanup(func() { invitationPollInterval = pollInterval })
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeInvitations, fakeProjects),
+		Steps: []resource.TestStep{
+			// The apply fails if the invitation is not accepted in time
+			{
+				Config:      fake.providerConfig() + testAccProjectInvitationWaitConfig(1),
+				ExpectError: regexp.MustCompile(`did not accept the invitation within 1s`),
+			},
+			// The tainted invitation is replaced and the apply waits until it is accepted
+			{
+				PreConfig: func() {
+					tainted := fake.list(fakeInvitations, "")
+					go func() {
+						// Accept the new invitation, once the tainted one is replaced
+						deadline := time.Now().Add(time.Minute)
+						for time.Now().Before(deadline) {
+							time.Sleep(200 * time.Millisecond)
+							invitations := fake.list(fakeInvitations, "")
+							if len(invitations) == 1 && fmt.Sprint(invitations) != fmt.Sprint(tainted) && fake.acceptInvitations() > 0 {
+								return
+							}
+						}
+					}()
+				},
+				Config: fake.providerConfig() + testAccProjectInvitationWaitConfig(60),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "status", "accepted"),
+					testAccCheckInvitationCount(fake, 0),
+				),
+			},
+		},
+	})
+}
+
+func TestChangeMemberRole(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeAPI(t)
+	client := fake.client()
+	api := client.API
+
+	maintainer, _, err := api.MaintainersAPI.CreateAMaintainer(ctx).CreateAMaintainerRequest(management.CreateAMaintainerRequest{Name: "tf-test-maintainer"}).Execute()
+	require.NoError(t, err)
+	organization, _, err := api.OrganizationsAPI.CreateAnOrganization(ctx, maintainer.GetId()).CreateAnOrganizationRequest(management.CreateAnOrganizationRequest{
+		Name: management.PtrString("tf-test-organization"),
+	}).Execute()
+	require.NoError(t, err)
+	project, _, err := api.ProjectsAPI.AddAProject(ctx, strconv.FormatInt(int64(organization.GetId()), 10)).AddAProjectRequest(management.AddAProjectRequest{Name: "tf-test-project", Type: "demo"}).Execute()
+	require.NoError(t, err)
+	projectID := strconv.FormatInt(int64(project.GetId()), 10)
+	_, _, err = api.ProjectsAPI.InviteAUserToAProject(ctx, projectID).InviteAUserToAProjectRequest(management.InviteAUserToAProjectRequest{
+		Email: "tf-test@example.com",
+		Role:  management.PtrString("guest"),
+	}).Execute()
+	require.NoError(t, err)
+	require.Equal(t, 1, fake.acceptInvitations())
+
+	// The member has a seven-digit ID, which must not be formatted in the exponent notation
+	members := fake.list(fakeProjectUsers, "")
+	require.Len(t, members, 1)
+	require.GreaterOrEqual(t, members[0].(map[string]interface{})["id"], float64(1000000))
+
+	r := &projectInvitationResource{client: client}
+	require.NoError(t, r.changeMemberRole(ctx, projectInvitationResourceModel{
+		ProjectID: types.StringValue(projectID),
+		Email:     types.StringValue("tf-test@example.com"),
+		Role:      types.StringValue("admin"),
+	}))
+	require.NoError(t, testAccCheckProjectMemberRole(fake, "admin")(nil))
+}
+
+func testAccCheckInvitationCount(fake *fakeAPI, expected int) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		if n := fake.count(fakeInvitations); n != expected {
+			return fmt.Errorf("expected %d invitations in the fake API, got %d", expected, n)
+		}
+		return nil
+	}
+}
+
+func testAccCheckProjectMemberRole(fake *fakeAPI, expected string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		members := fake.list(fakeProjectUsers, "")
+		if len(members) != 1 {
+			return fmt.Errorf("expected 1 project member in the fake API, got %d", len(members))
+		}
+		if role := members[0].(map[string]interface{})["role"]; role != expected {
+			return fmt.Errorf("expected project member role %q, got %v", expected, role)
+		}
+		return nil
+	}
+}
+
+func testAccProjectInvitationResendConfig(trigger string) string {
+	return testAccProjectConfig("tf-test-project") + fmt.Sprintf(`
+resource "keboola-management_project_invitation" "test" {
+  project_id     = keboola-management_project.test.id
+  email          = "tf-test-user@example.com"
+  role           = "guest"
+  resend_trigger = %q
+}
+`, trigger)
+}
+
+func testAccProjectInvitationWaitConfig(timeout int) string {
+	return testAccProjectConfig("tf-test-project") + fmt.Sprintf(`
+resource "keboola-management_project_invitation" "test" {
+  project_id                  = keboola-management_project.test.id
+  email                       = "tf-test-user@example.com"
+  role                        = "guest"
+  wait_for_acceptance         = true
+  wait_for_acceptance_timeout = %d
+}
+`, timeout)
+}
+
+func testAccProjectInvitationAttributesConfig(projectID, email, role string) string {
+	return fmt.Sprintf(`
+resource "keboola-management_project_invitation" "test" {
+  project_id = %q
+  email      = %q
+  role       = %q
+}
+`, projectID, email, role)
+}
+
+func testAccProjectInvitationConfig(role string) string {
+	return testAccProjectConfig("tf-test-project") + fmt.Sprintf(`
+resource "keboola-management_project_invitation" "test" {
+  project_id = keboola-management_project.test.id
+  email      = "tf-test-user@example.com"
+  role       = %q
+  reason     = "Terraform acceptance test"
+}
+`, role)
+}
@@ -0,0 +1,115 @@
+package keboola
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccProjectResource(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_project.test"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeProjects, fakeOrganizations, fakeMaintainers),
+		Steps: []resource.TestStep{
+			// Create and read
+			{
+				Config: fake.providerConfig() + testAccProjectConfig("tf-test-project"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckResourceAttr(name, "name", "tf-test-project"),
+					resource.TestCheckResourceAttr(name, "type", "demo"),
+					fake.checkField(fakeProjects, &id, "dataRetentionTimeInDays", 7),
+				),
+			},
+			// Import
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Only the name is read back from the API
+				ImportStateVerifyIgnore: []string{"organization_id", "type", "default_backend", "data_retention_time_in_days"},
+			},
+			// Update
+			{
+				Config: fake.providerConfig() + testAccProjectConfig("tf-test-project-renamed"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "name", "tf-test-project-renamed"),
+					fake.checkField(fakeProjects, &id, "name", "tf-test-project-renamed"),
+				),
+			},
+			// Drift
+			{
+				PreConfig: func() {
+					fake.update(fakeProjects, id, func(data map[string]interface{}) {
+						data["name"] = "changed outside of Terraform"
+					})
+				},
+				Config:             fake.providerConfig() + testAccProjectConfig("tf-test-project-renamed"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccProjectResource_validation(t *testing.T) {
+	fake := newFakeAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fake.providerConfig() + testAccProjectAttributesConfig(1, "trial", "snowflake", 7),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			{
+				Config:      fake.providerConfig() + testAccProjectAttributesConfig(1, "demo", "postgres", 7),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			{
+				Config:      fake.providerConfig() + testAccProjectAttributesConfig(0, "demo", "snowflake", 7),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value must be at least 1`),
+			},
+			{
+				Config:      fake.providerConfig() + testAccProjectAttributesConfig(1, "demo", "snowflake", -1),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value must be at least 0`),
+			},
+		},
+	})
+}
+
+func testAccProjectAttributesConfig(organizationID int, projectType, defaultBackend string, retention int) string {
+	return fmt.Sprintf(`
+resource "keboola-management_project" "test" {
+  name                        = "tf-test-project"
+  organization_id             = %d
+  type                        = %q
+  default_backend             = %q
+  data_retention_time_in_days = %d
+}
+`, organizationID, projectType, defaultBackend, retention)
+}
+
+// testAccProjectConfig returns a project with its organization and maintainer,
+// tests of resources nested in a project build on it.
+func testAccProjectConfig(name string) string {
+	return testAccOrganizationConfig("tf-test-organization") + fmt.Sprintf(`
+resource "keboola-management_project" "test" {
+  name                        = %q
+  organization_id             = keboola-management_organization.test.id
+  type                        = "demo"
+  default_backend             = "snowflake"
+  data_retention_time_in_days = 7
+}
+`, name)
+}
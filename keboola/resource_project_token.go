@@ -2,20 +2,27 @@ package keboola
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/numberplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	sdk "github.com/keboola/keboola-sdk-go/v2/pkg/keboola"
 	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
+	"github.com/relvacode/iso8601"
 )
 
 // Ensure the implementation satisfies the expected interfaces
@@ -23,6 +30,7 @@ var (
 	_ resource.Resource                = &projectTokenResource{}
 	_ resource.ResourceWithConfigure   = &projectTokenResource{}
 	_ resource.ResourceWithImportState = &projectTokenResource{}
+	_ resource.ResourceWithModifyPlan  = &projectTokenResource{}
 )
 
 // NewProjectTokenResource returns a new keboola_project_token resource.
@@ -37,16 +45,137 @@ type projectTokenResource struct {
 
 // projectTokenResourceModel maps the resource schema data.
 type projectTokenResourceModel struct {
-	ID                    types.String `tfsdk:"id"`
+	ID types.String `tfsdk:"id"`
+	projectTokenSettingsModel
+	ExpiresIn types.Number `tfsdk:"expires_in"`
+	Expires   types.String `tfsdk:"expires"`
+	Token     types.String `tfsdk:"token"`
+
+	Created          types.String `tfsdk:"created"`
+	RotationDays     types.Int64  `tfsdk:"rotation_days"`
+	RotateBeforeDays types.Int64  `tfsdk:"rotate_before_days"`
+
+	PGPKey         types.String `tfsdk:"pgp_key"`
+	EncryptedToken types.String `tfsdk:"encrypted_token"`
+	KeyFingerprint types.String `tfsdk:"key_fingerprint"`
+}
+
+// projectTokenSettingsModel maps the token attributes shared with the ephemeral project token.
+type projectTokenSettingsModel struct {
 	ProjectID             types.String `tfsdk:"project_id"`
 	Description           types.String `tfsdk:"description"`
 	CanManageBuckets      types.Bool   `tfsdk:"can_manage_buckets"`
 	CanReadAllFileUploads types.Bool   `tfsdk:"can_read_all_file_uploads"`
 	CanPurgeTrash         types.Bool   `tfsdk:"can_purge_trash"`
-	ExpiresIn             types.Number `tfsdk:"expires_in"`
 	BucketPermissions     types.Map    `tfsdk:"bucket_permissions"`
 	ComponentAccess       types.List   `tfsdk:"component_access"`
-	Token                 types.String `tfsdk:"token"`
+}
+
+// createRequest builds the API request body for token creation.
+// The SDK model of the bucket permissions covers only the "in.c" bucket, so the whole map is set on the encoded body.
+func (m projectTokenSettingsModel) createRequest(ctx context.Context, expiresIn *float32) (map[string]interface{}, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	tokenBody := management.CreateStorageTokenRequest{
+		Description: m.Description.ValueString(),
+		ExpiresIn:   expiresIn,
+	}
+	if !m.CanManageBuckets.IsNull() {
+		canManageBuckets := m.CanManageBuckets.ValueBool()
+		tokenBody.CanManageBuckets = &canManageBuckets
+	}
+	if !m.CanReadAllFileUploads.IsNull() {
+		canReadAllFileUploads := m.CanReadAllFileUploads.ValueBool()
+		tokenBody.CanReadAllFileUploads = &canReadAllFileUploads
+	}
+	if !m.CanPurgeTrash.IsNull() {
+		canPurgeTrash := m.CanPurgeTrash.ValueBool()
+		tokenBody.CanPurgeTrash = &canPurgeTrash
+	}
+	if !m.ComponentAccess.IsNull() && !m.ComponentAccess.IsUnknown() {
+		var access []string
+		diags.Append(m.ComponentAccess.ElementsAs(ctx, &access, false)...)
+		if !diags.HasError() {
+			tokenBody.SetComponentAccess(access)
+		}
+	}
+
+	body, err := tokenBody.ToMap()
+	if err != nil {
+		diags.AddError("Error creating storage token", "Could not encode the token request: "+err.Error())
+		return nil, diags
+	}
+	if !m.BucketPermissions.IsNull() && !m.BucketPermissions.IsUnknown() {
+		var perms map[string]string
+		diags.Append(m.BucketPermissions.ElementsAs(ctx, &perms, false)...)
+		body["bucketPermissions"] = perms
+	}
+	return body, diags
+}
+
+// updateRequest builds the API request body for the token update.
+// All updatable settings are sent, so removing an attribute from the configuration revokes the permission.
+func (m projectTokenSettingsModel) updateRequest(ctx context.Context) (map[string]interface{}, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	perms := map[string]string{}
+	if !m.BucketPermissions.IsNull() {
+		diags.Append(m.BucketPermissions.ElementsAs(ctx, &perms, false)...)
+	}
+	access := []string{}
+	if !m.ComponentAccess.IsNull() {
+		diags.Append(m.ComponentAccess.ElementsAs(ctx, &access, false)...)
+	}
+	return map[string]interface{}{
+		"description":           m.Description.ValueString(),
+		"canManageBuckets":      m.CanManageBuckets.ValueBool(),
+		"canReadAllFileUploads": m.CanReadAllFileUploads.ValueBool(),
+		"canPurgeTrash":         m.CanPurgeTrash.ValueBool(),
+		"bucketPermissions":     perms,
+		"componentAccess":       access,
+	}, diags
+}
+
+// equal reports whether the settings are the same, so no update of the token is needed.
+func (m projectTokenSettingsModel) equal(other projectTokenSettingsModel) bool {
+	return m.ProjectID.Equal(other.ProjectID) &&
+		m.Description.Equal(other.Description) &&
+		m.CanManageBuckets.Equal(other.CanManageBuckets) &&
+		m.CanReadAllFileUploads.Equal(other.CanReadAllFileUploads) &&
+		m.CanPurgeTrash.Equal(other.CanPurgeTrash) &&
+		m.BucketPermissions.Equal(other.BucketPermissions) &&
+		m.ComponentAccess.Equal(other.ComponentAccess)
+}
+
+// refresh updates the configured attributes from the token returned by the Storage API.
+// Attributes which are not configured are kept null, so the API defaults do not cause a diff.
+func (m *projectTokenSettingsModel) refresh(ctx context.Context, token *sdk.Token) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !m.Description.IsNull() {
+		m.Description = types.StringValue(token.Description)
+	}
+	if !m.CanManageBuckets.IsNull() {
+		m.CanManageBuckets = types.BoolValue(token.CanManageBuckets)
+	}
+	if !m.CanReadAllFileUploads.IsNull() {
+		m.CanReadAllFileUploads = types.BoolValue(token.CanReadAllFileUploads)
+	}
+	if !m.CanPurgeTrash.IsNull() {
+		m.CanPurgeTrash = types.BoolValue(token.CanPurgeTrash)
+	}
+	if !m.BucketPermissions.IsNull() {
+		perms := make(map[string]string, len(token.BucketPermissions))
+		for bucketID, permission := range token.BucketPermissions {
+			perms[bucketID.String()] = string(permission)
+		}
+		permissions, d := types.MapValueFrom(ctx, types.StringType, perms)
+		diags.Append(d...)
+		m.BucketPermissions = permissions
+	}
+	if !m.ComponentAccess.IsNull() {
+		access, d := types.ListValueFrom(ctx, types.StringType, token.ComponentAccess)
+		diags.Append(d...)
+		m.ComponentAccess = access
+	}
+	return diags
 }
 
 // Configure adds the provider configured client to the resource.
@@ -65,7 +194,8 @@ func (r *projectTokenResource) Metadata(_ context.Context, req resource.Metadata
 // Schema defines the schema for the resource.
 func (r *projectTokenResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Manages a Keboola project storage token. The token is a one-time secret and cannot be read after creation.",
+		Description: "Manages a Keboola project storage token. The token is a one-time secret and cannot be read after creation. " +
+			"The description and the permissions are updated in place, other changes replace the token.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "Token ID.",
@@ -77,6 +207,7 @@ func (r *projectTokenResource) Schema(_ context.Context, _ resource.SchemaReques
 			"project_id": schema.StringAttribute{
 				Description: "ID of the Keboola project.",
 				Required:    true,
+				Validators:  numericIDValidators(),
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(), // Changing project requires new token
 				},
@@ -84,30 +215,18 @@ func (r *projectTokenResource) Schema(_ context.Context, _ resource.SchemaReques
 			"description": schema.StringAttribute{
 				Description: "Token description.",
 				Optional:    true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
 			},
 			"can_manage_buckets": schema.BoolAttribute{
 				Description: "Token has full permissions on tabular storage.",
 				Optional:    true,
-				PlanModifiers: []planmodifier.Bool{
-					boolplanmodifier.RequiresReplace(),
-				},
 			},
 			"can_read_all_file_uploads": schema.BoolAttribute{
 				Description: "Token has full permissions to files staging.",
 				Optional:    true,
-				PlanModifiers: []planmodifier.Bool{
-					boolplanmodifier.RequiresReplace(),
-				},
 			},
 			"can_purge_trash": schema.BoolAttribute{
 				Description: "Allows permanently removing deleted configurations.",
 				Optional:    true,
-				PlanModifiers: []planmodifier.Bool{
-					boolplanmodifier.RequiresReplace(),
-				},
 			},
 			"expires_in": schema.NumberAttribute{
 				Description: "Token lifetime in seconds.",
@@ -116,24 +235,75 @@ func (r *projectTokenResource) Schema(_ context.Context, _ resource.SchemaReques
 					numberplanmodifier.RequiresReplace(),
 				},
 			},
+			"expires": schema.StringAttribute{
+				Description: "Expiration time of the token in RFC 3339 format, null if the token does not expire.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created": schema.StringAttribute{
+				Description: "Creation time of the token in RFC 3339 format, the scheduled rotation is counted from it.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"rotation_days": schema.Int64Attribute{
+				Description: "Replaces the token once it is older than the given number of days. " +
+					"Without the create_before_destroy lifecycle, the old token is deleted before the new one is created, " +
+					"so consumers of the token fail until they get the new value. With it, the new token is created first and passed to the resources referencing it before the old token is deleted.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"rotate_before_days": schema.Int64Attribute{
+				Description: "Number of days before the rotation is due in which the token is already replaced, e.g. to match the schedule of the Terraform runs. Defaults to 0.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(0),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					int64validator.AlsoRequires(path.MatchRoot("rotation_days")),
+				},
+			},
 			"bucket_permissions": schema.MapAttribute{
-				Description: "Map of bucket permissions, e.g., {\"in.c\": \"main: read\"}.",
+				Description: "Map of bucket IDs to the permission granted to the token, `read` or `write`, e.g., {\"in.c-main\": \"read\"}.",
 				Optional:    true,
 				ElementType: types.StringType,
-				PlanModifiers: []planmodifier.Map{
-					mapplanmodifier.RequiresReplace(),
-				},
+				Validators:  projectTokenBucketPermissionsValidators(),
 			},
 			"component_access": schema.ListAttribute{
 				Description: "List of component IDs to grant access for component configurations.",
 				Optional:    true,
 				ElementType: types.StringType,
-				PlanModifiers: []planmodifier.List{
-					listplanmodifier.RequiresReplace(),
+			},
+			"pgp_key": schema.StringAttribute{
+				Description: "Armored PGP public key or a `keybase:username` reference. If set, the token is encrypted with the key " +
+					"and only the encrypted_token is stored in the state. The token is then read, updated and deleted by ID.",
+				Optional:   true,
+				Validators: []validator.String{pgpKeyValidator{}},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"encrypted_token": schema.StringAttribute{
+				Description: "Token value encrypted with the pgp_key, base64 encoded. Decrypt it e.g. with `base64 -d | gpg --decrypt`.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key_fingerprint": schema.StringAttribute{
+				Description: "Fingerprint of the pgp_key used to encrypt the token.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
 			"token": schema.StringAttribute{
-				Description: "Token value.",
+				Description: "Token value, null if pgp_key is set.",
 				Computed:    true,
 				Sensitive:   true,
 				PlanModifiers: []planmodifier.String{
@@ -155,49 +325,32 @@ func (r *projectTokenResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	// Build API request body for token creation
-	tokenBody := management.CreateStorageTokenRequest{
-		Description: plan.Description.ValueString(),
-	}
-	if !plan.CanManageBuckets.IsNull() {
-		canManageBuckets := plan.CanManageBuckets.ValueBool()
-		tokenBody.CanManageBuckets = &canManageBuckets
-	}
-	if !plan.CanReadAllFileUploads.IsNull() {
-		canReadAllFileUploads := plan.CanReadAllFileUploads.ValueBool()
-		tokenBody.CanReadAllFileUploads = &canReadAllFileUploads
-	}
-	if !plan.CanPurgeTrash.IsNull() {
-		canPurgeTrash := plan.CanPurgeTrash.ValueBool()
-		tokenBody.CanPurgeTrash = &canPurgeTrash
+	// The PGP key is retrieved first, so an invalid key does not leave an orphaned token
+	var pgpKey *openpgp.Entity
+	if !plan.PGPKey.IsNull() {
+		var err error
+		if pgpKey, err = retrievePGPKey(ctx, r.client.BaseHTTPClient, plan.PGPKey.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Error creating storage token", "Could not retrieve the PGP key: "+err.Error())
+			return
+		}
 	}
+
+	// Build API request body for token creation
+	var expiresIn *float32
 	if !plan.ExpiresIn.IsNull() {
 		bigVal := plan.ExpiresIn.ValueBigFloat()
 		f64, _ := bigVal.Float64()
 		converted := float32(f64)
-		tokenBody.ExpiresIn = &converted
+		expiresIn = &converted
 	}
-	if !plan.BucketPermissions.IsNull() && !plan.BucketPermissions.IsUnknown() {
-		var perms map[string]string
-		diags := plan.BucketPermissions.ElementsAs(ctx, &perms, false)
-		resp.Diagnostics.Append(diags...)
-		if !resp.Diagnostics.HasError() {
-			permissions := management.NewCreateStorageTokenRequestBucketPermissions()
-			permissions.SetInC(perms["in.c"])
-			tokenBody.BucketPermissions = permissions
-		}
-	}
-	if !plan.ComponentAccess.IsNull() && !plan.ComponentAccess.IsUnknown() {
-		var access []string
-		diags := plan.ComponentAccess.ElementsAs(ctx, &access, false)
-		resp.Diagnostics.Append(diags...)
-		if !resp.Diagnostics.HasError() {
-			tokenBody.SetComponentAccess(access)
-		}
+	tokenBody, diags := plan.createRequest(ctx, expiresIn)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	// Create the storage token
-	tokenResp, _, err := r.client.API.ProjectsAPI.CreateStorageToken(ctx, plan.ProjectID.ValueString()).CreateStorageTokenRequest(tokenBody).Execute()
+	tokenResp, err := r.client.createProjectToken(ctx, plan.ProjectID.ValueString(), tokenBody)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating storage token", "Could not create storage token: "+err.Error())
 		return
@@ -205,6 +358,36 @@ func (r *projectTokenResource) Create(ctx context.Context, req resource.CreateRe
 
 	plan.ID = types.StringValue(*tokenResp.Id)
 	plan.Token = types.StringValue(*tokenResp.Token)
+	plan.EncryptedToken = types.StringNull()
+	plan.KeyFingerprint = types.StringNull()
+	if pgpKey != nil {
+		encrypted, fingerprint, err := encryptWithPGPKey(pgpKey, *tokenResp.Token)
+		if err != nil {
+			resp.Diagnostics.AddError("Error creating storage token", fmt.Sprintf("Could not encrypt storage token %s: %s", *tokenResp.Id, err.Error()))
+			return
+		}
+		plan.Token = types.StringNull()
+		plan.EncryptedToken = types.StringValue(encrypted)
+		plan.KeyFingerprint = types.StringValue(fingerprint)
+	}
+	plan.Expires = types.StringNull()
+	if expires, ok := tokenResp.Expires.(string); ok && expires != "" {
+		expiresAt, err := iso8601.ParseString(expires)
+		if err != nil {
+			resp.Diagnostics.AddError("Error creating storage token", "Could not parse the token expiration: "+err.Error())
+			return
+		}
+		plan.Expires = types.StringValue(formatTokenTime(expiresAt))
+	}
+	plan.Created = types.StringNull()
+	if tokenResp.Created != nil {
+		created, err := iso8601.ParseString(*tokenResp.Created)
+		if err != nil {
+			resp.Diagnostics.AddError("Error creating storage token", "Could not parse the token creation time: "+err.Error())
+			return
+		}
+		plan.Created = types.StringValue(formatTokenTime(created))
+	}
 
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
@@ -214,7 +397,14 @@ func (r *projectTokenResource) Create(ctx context.Context, req resource.CreateRe
 	}
 }
 
-// Read refreshes the Terraform state. The token is not available after creation.
+// formatTokenTime formats the token timestamps the same way after creation and refresh.
+func formatTokenTime(value time.Time) string {
+	return value.UTC().Format(time.RFC3339)
+}
+
+// Read refreshes the Terraform state from the Storage API, authorized by the token itself.
+// Tokens whose value is not known, after import or with pgp_key, are read by ID instead.
+// Tokens which were revoked, disabled or have expired are removed from the state, so they are created again.
 func (r *projectTokenResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	// Get current state
 	var state projectTokenResourceModel
@@ -224,6 +414,33 @@ func (r *projectTokenResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
+	token, ok := r.readToken(ctx, &state, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !ok {
+		tflog.Warn(ctx, fmt.Sprintf("Storage token %s is no longer valid, removing it from state", state.ID.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if token.IsExpired || token.IsDisabled || (token.Expires != nil && !token.Expires.Time.After(time.Now())) {
+		tflog.Warn(ctx, fmt.Sprintf("Storage token %s has expired or is disabled, removing it from state", token.ID))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Overwrite items with refreshed state
+	state.ID = types.StringValue(token.ID)
+	state.Expires = types.StringNull()
+	if token.Expires != nil {
+		state.Expires = types.StringValue(formatTokenTime(token.Expires.Time))
+	}
+	state.Created = types.StringValue(formatTokenTime(token.Created.Time))
+	resp.Diagnostics.Append(state.refresh(ctx, token)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -232,18 +449,118 @@ func (r *projectTokenResource) Read(ctx context.Context, req resource.ReadReques
 	}
 }
 
-// Update forces recreation on any change.
+// readToken returns the token from the Storage API, false if it was revoked.
+func (r *projectTokenResource) readToken(ctx context.Context, state *projectTokenResourceModel, diags *diag.Diagnostics) (*sdk.Token, bool) {
+	if state.Token.ValueString() == "" {
+		token, err := r.client.getProjectToken(ctx, state.ProjectID.ValueString(), state.ID.ValueString())
+		if err != nil {
+			diags.AddError(
+				"Error reading storage token",
+				"Could not read storage token "+state.ID.ValueString()+": "+err.Error(),
+			)
+			return nil, false
+		}
+		return token, token != nil
+	}
+
+	client, err := r.client.NewStorageAPI(ctx, state.Token.ValueString())
+	if err != nil {
+		diags.AddError(
+			"Error creating authorized API client",
+			"Could not create authorized API client: "+err.Error(),
+		)
+		return nil, false
+	}
+
+	token, err := client.VerifyTokenRequest(state.Token.ValueString()).Send(ctx)
+	var storageErr *sdk.StorageError
+	if errors.As(err, &storageErr) && (storageErr.StatusCode() == http.StatusUnauthorized || storageErr.StatusCode() == http.StatusNotFound) {
+		return nil, false
+	}
+	if err != nil {
+		diags.AddError(
+			"Error reading storage token",
+			"Could not verify storage token "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return nil, false
+	}
+	return token, true
+}
+
+// Update changes the description and the permissions of the token in place via the Storage API.
 func (r *projectTokenResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Any change requires recreation, so just call Create.
-	r.Create(ctx, resource.CreateRequest{
-		Plan: req.Plan,
-	}, &resource.CreateResponse{
-		State:       resp.State,
-		Diagnostics: resp.Diagnostics,
-	})
+	var plan, state projectTokenResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The rotation settings are stored in the state only
+	if !plan.projectTokenSettingsModel.equal(state.projectTokenSettingsModel) {
+		tokenBody, diags := plan.updateRequest(ctx)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		err := r.client.updateProjectToken(ctx, state.ProjectID.ValueString(), state.ID.ValueString(), tokenBody)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating storage token",
+				"Could not update storage token "+state.ID.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	state.projectTokenSettingsModel = plan.projectTokenSettingsModel
+	state.RotationDays = plan.RotationDays
+	state.RotateBeforeDays = plan.RotateBeforeDays
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
-// Delete removes the resource from state. No API call is needed.
+// ModifyPlan replaces the token once its scheduled rotation is due.
+func (r *projectTokenResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to rotate on create and destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state projectTokenResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.RotationDays.IsNull() || plan.RotationDays.IsUnknown() || plan.RotateBeforeDays.IsUnknown() || state.Created.IsNull() {
+		return
+	}
+
+	created, err := time.Parse(time.RFC3339, state.Created.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error planning storage token rotation", "Could not parse the token creation time: "+err.Error())
+		return
+	}
+	rotateAt := tokenRotationTime(created, plan.RotationDays.ValueInt64(), plan.RotateBeforeDays.ValueInt64())
+	if time.Now().Before(rotateAt) {
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Storage token %s is due for rotation since %s, replacing it", state.ID.ValueString(), rotateAt.Format(time.RFC3339)))
+	for _, attr := range []string{"id", "token", "created", "expires"} {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(attr), types.StringUnknown())...)
+	}
+	resp.RequiresReplace = append(resp.RequiresReplace, path.Root("created"))
+}
+
+// tokenRotationTime returns the time from which the token is replaced.
+func tokenRotationTime(created time.Time, rotationDays, rotateBeforeDays int64) time.Time {
+	return created.AddDate(0, 0, int(rotationDays-rotateBeforeDays))
+}
+
+// Delete deletes the token via the Storage API and removes it from the state.
 func (r *projectTokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state projectTokenResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -260,8 +577,21 @@ func (r *projectTokenResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
+	// The token value is not known after import or with pgp_key, so the token is deleted by ID
+	if state.Token.ValueString() == "" {
+		if err := r.client.deleteProjectToken(ctx, state.ProjectID.ValueString(), tokenID); err != nil {
+			resp.Diagnostics.AddError(
+				"Error deleting storage token",
+				"Could not delete storage token: "+err.Error(),
+			)
+			return
+		}
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
 	tflog.Info(ctx, "Creating authorized API client for token deletion")
-	client, err := sdk.NewAuthorizedAPI(ctx, r.client.API.GetConfig().Host, state.Token.ValueString())
+	client, err := r.client.NewStorageAPI(ctx, state.Token.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating authorized API client",
@@ -286,6 +616,14 @@ func (r *projectTokenResource) Delete(ctx context.Context, req resource.DeleteRe
 
 // ImportState imports an existing resource into Terraform.
 func (r *projectTokenResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import by ID (token value, but not available after creation)
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Import by project_id:token_id, the token value is not available after creation
+	importCompositeID(ctx, req, resp, "project_id:token_id", "project_id", "id")
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.AddWarning(
+		"Token value not imported",
+		"The token value can be read only when the token is created, so the token attribute stays empty after import. "+
+			"Replace the resource to get the token value in the state.",
+	)
 }
@@ -0,0 +1,498 @@
+package keboola
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccProjectTokenResource(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_project_token.test"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeTokens, fakeProjects),
+		Steps: []resource.TestStep{
+			// Create and read
+			{
+				Config: fake.providerConfig() + testAccProjectTokenConfig("tf-test-token"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckResourceAttr(name, "description", "tf-test-token"),
+					resource.TestCheckResourceAttrSet(name, "token"),
+					resource.TestCheckResourceAttrSet(name, "expires"),
+					fake.checkField(fakeTokens, &id, "canManageBuckets", true),
+				),
+			},
+			// Import by project_id:token_id, the token value and the request attributes cannot be read back
+			{
+				ResourceName:      name,
+				ImportState:       true,
+				ImportStateIdFunc: testAccCompositeImportID(name, "project_id", "id"),
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 imported token, got %d", len(states))
+					}
+					if states[0].ID != id || states[0].Attributes["project_id"] == "" {
+						return fmt.Errorf("unexpected imported token %q in project %q", states[0].ID, states[0].Attributes["project_id"])
+					}
+					return nil
+				},
+			},
+			{
+				ResourceName:  name,
+				ImportState:   true,
+				ImportStateId: "123:",
+				ExpectError:   regexp.MustCompile(`Expected import identifier with format: project_id:token_id`),
+			},
+			// The description is updated in place
+			{
+				Config: fake.providerConfig() + testAccProjectTokenConfig("tf-test-token-updated"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", id),
+					resource.TestCheckResourceAttr(name, "description", "tf-test-token-updated"),
+					fake.checkField(fakeTokens, &id, "description", "tf-test-token-updated"),
+					testAccCheckTokenCount(fake, 1),
+				),
+			},
+			// Drift of the permissions
+			{
+				PreConfig: func() {
+					fake.update(fakeTokens, id, func(data map[string]interface{}) {
+						data["canManageBuckets"] = false
+					})
+				},
+				Config:             fake.providerConfig() + testAccProjectTokenConfig("tf-test-token-updated"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// A revoked token is removed from state and created again
+			{
+				PreConfig: func() {
+					fake.remove(fakeTokens, id)
+				},
+				Config: fake.providerConfig() + testAccProjectTokenConfig("tf-test-token-updated"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(s *terraform.State) error {
+						if s.RootModule().Resources[name].Primary.ID == id {
+							return fmt.Errorf("expected a new token, got the revoked token %s", id)
+						}
+						return nil
+					},
+					testAccCaptureID(name, &id),
+					testAccCheckTokenCount(fake, 1),
+				),
+			},
+			// An expired token is removed from state and created again
+			{
+				PreConfig: func() {
+					fake.update(fakeTokens, id, func(data map[string]interface{}) {
+						data["isExpired"] = true
+					})
+				},
+				Config:             fake.providerConfig() + testAccProjectTokenConfig("tf-test-token-updated"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccProjectTokenResource_update(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_project_token.test"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeTokens, fakeProjects),
+		Steps: []resource.TestStep{
+			// Create
+			{
+				Config: fake.providerConfig() + testAccProjectTokenUpdateConfig(`
+  can_read_all_file_uploads = false
+  component_access          = ["keboola.ex-db-snowflake"]
+  bucket_permissions        = { "in.c-main" = "read" }`, 3600),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+				),
+			},
+			// The permissions are updated in place
+			{
+				Config: fake.providerConfig() + testAccProjectTokenUpdateConfig(`
+  can_read_all_file_uploads = true
+  component_access          = ["keboola.ex-db-snowflake", "keboola.wr-db-snowflake"]
+  bucket_permissions        = { "in.c-main" = "write", "out.c-reporting" = "read" }`, 3600),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", id),
+					resource.TestCheckResourceAttr(name, "component_access.#", "2"),
+					fake.checkField(fakeTokens, &id, "canReadAllFileUploads", true),
+					fake.checkField(fakeTokens, &id, "componentAccess", []interface{}{"keboola.ex-db-snowflake", "keboola.wr-db-snowflake"}),
+					fake.checkField(fakeTokens, &id, "bucketPermissions", map[string]interface{}{"in.c-main": "write", "out.c-reporting": "read"}),
+				),
+			},
+			// Removed permissions are revoked
+			{
+				Config: fake.providerConfig() + testAccProjectTokenUpdateConfig("", 3600),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", id),
+					fake.checkField(fakeTokens, &id, "canReadAllFileUploads", false),
+					fake.checkField(fakeTokens, &id, "componentAccess", []interface{}{}),
+					fake.checkField(fakeTokens, &id, "bucketPermissions", map[string]interface{}{}),
+				),
+			},
+			// The expiration cannot be changed in place
+			{
+				Config: fake.providerConfig() + testAccProjectTokenUpdateConfig("", 7200),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(s *terraform.State) error {
+						if s.RootModule().Resources[name].Primary.ID == id {
+							return fmt.Errorf("expected the token %s to be replaced", id)
+						}
+						return nil
+					},
+					testAccCheckTokenCount(fake, 1),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProjectTokenResource_pgpKey(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_project_token.test"
+	entity, armored := testPGPKey(t)
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeTokens, fakeProjects),
+		Steps: []resource.TestStep{
+			{
+				Config:      fake.providerConfig() + testAccProjectTokenPGPConfig("not a key"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid PGP key`),
+			},
+			// Only the encrypted token is stored
+			{
+				Config: fake.providerConfig() + testAccProjectTokenPGPConfig(armored),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckNoResourceAttr(name, "token"),
+					resource.TestCheckResourceAttr(name, "key_fingerprint", hex.EncodeToString(entity.PrimaryKey.Fingerprint)),
+					func(s *terraform.State) error {
+						encrypted := s.RootModule().Resources[name].Primary.Attributes["encrypted_token"]
+						if decrypted := testPGPDecrypt(t, entity, encrypted); decrypted != fmt.Sprintf("%s-%s-fake-storage-token", s.RootModule().Resources[name].Primary.Attributes["project_id"], id) {
+							return fmt.Errorf("unexpected decrypted token %q", decrypted)
+						}
+						return nil
+					},
+				),
+			},
+			// The encrypted token is read by ID, so its revocation is detected
+			{
+				PreConfig: func() {
+					fake.remove(fakeTokens, id)
+				},
+				Config:             fake.providerConfig() + testAccProjectTokenPGPConfig(armored),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccProjectTokenResource_rotation(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_project_token.test"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeTokens, fakeProjects),
+		Steps: []resource.TestStep{
+			// Create, the rotation is not due yet
+			{
+				Config: fake.providerConfig() + testAccProjectTokenRotationConfig(90, 7),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckResourceAttrSet(name, "created"),
+					resource.TestCheckResourceAttr(name, "rotation_days", "90"),
+					resource.TestCheckResourceAttr(name, "rotate_before_days", "7"),
+				),
+			},
+			// Change of the rotation settings keeps the token
+			{
+				Config: fake.providerConfig() + testAccProjectTokenRotationConfig(30, 0),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(name, "id", id),
+					resource.TestCheckResourceAttr(name, "rotation_days", "30"),
+				),
+			},
+			// The token is replaced once the rotation is due, the new one is created first
+			{
+				PreConfig: func() {
+					fake.update(fakeTokens, id, func(data map[string]interface{}) {
+						data["created"] = fakeTimestamp(time.Now().AddDate(0, 0, -31))
+					})
+				},
+				Config: fake.providerConfig() + testAccProjectTokenRotationConfig(30, 0),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(s *terraform.State) error {
+						if s.RootModule().Resources[name].Primary.ID == id {
+							return fmt.Errorf("expected the token %s to be rotated", id)
+						}
+						return nil
+					},
+					testAccCheckTokenCount(fake, 1),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProjectTokenResource_bucketPermissions(t *testing.T) {
+	fake := newFakeAPI(t)
+	name := "keboola-management_project_token.test"
+	var id string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             fake.checkDestroy(fakeTokens, fakeProjects),
+		Steps: []resource.TestStep{
+			// Only read and write permissions can be granted
+			{
+				Config:      fake.providerConfig() + testAccProjectTokenBucketPermissionsConfig(`"in.c-main" = "manage"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			{
+				Config:      fake.providerConfig() + testAccProjectTokenBucketPermissionsConfig(`"main" = "read"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must be a bucket ID`),
+			},
+			// All permissions are sent to the API
+			{
+				Config: fake.providerConfig() + testAccProjectTokenBucketPermissionsConfig(`
+    "in.c-main"        = "read"
+    "out.c-reporting"  = "write"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCaptureID(name, &id),
+					resource.TestCheckResourceAttr(name, "bucket_permissions.%", "2"),
+					fake.checkField(fakeTokens, &id, "bucketPermissions", map[string]interface{}{
+						"in.c-main":       "read",
+						"out.c-reporting": "write",
+					}),
+				),
+			},
+			// Drift of the granted permissions
+			{
+				PreConfig: func() {
+					fake.update(fakeTokens, id, func(data map[string]interface{}) {
+						data["bucketPermissions"] = map[string]interface{}{"in.c-main": "read"}
+					})
+				},
+				Config: fake.providerConfig() + testAccProjectTokenBucketPermissionsConfig(`
+    "in.c-main"        = "read"
+    "out.c-reporting"  = "write"`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestProjectTokenCreateRequest(t *testing.T) {
+	ctx := context.Background()
+	perms, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"in.c-main": "read", "out.c-reporting": "write"})
+	settings := projectTokenSettingsModel{
+		ProjectID:             types.StringValue("1"),
+		Description:           types.StringValue("tf-test"),
+		CanManageBuckets:      types.BoolNull(),
+		CanReadAllFileUploads: types.BoolValue(true),
+		CanPurgeTrash:         types.BoolNull(),
+		BucketPermissions:     perms,
+		ComponentAccess:       types.ListNull(types.StringType),
+	}
+	expiresIn := float32(3600)
+
+	request, diags := settings.createRequest(ctx, &expiresIn)
+	require.False(t, diags.HasError(), diags)
+	encoded, err := json.Marshal(request)
+	require.NoError(t, err)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &body))
+
+	assert.Equal(t, map[string]interface{}{"in.c-main": "read", "out.c-reporting": "write"}, body["bucketPermissions"])
+	assert.Equal(t, "tf-test", body["description"])
+	assert.Equal(t, true, body["canReadAllFileUploads"])
+	assert.Equal(t, float64(3600), body["expiresIn"])
+	assert.NotContains(t, body, "canManageBuckets")
+}
+
+func TestUpdateProjectToken(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeAPI(t)
+	client := fake.client()
+	projectID, token := testProjectTokenFixture(t, client)
+	tokenID := token.GetId()
+	body := map[string]interface{}{"description": "tf-test-token-updated", "canReadAllFileUploads": true}
+
+	// A token without the permission to manage tokens cannot update itself
+	err := client.sendStorageRequest(ctx, token.GetToken(), http.MethodPut, "/v2/storage/tokens/"+tokenID, body, nil)
+	var apiErr *apiError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+	// The update is authorized by a short-lived token, which is deleted afterwards
+	require.NoError(t, client.updateProjectToken(ctx, projectID, tokenID, body))
+	require.NoError(t, fake.checkField(fakeTokens, &tokenID, "description", "tf-test-token-updated")(nil))
+	require.NoError(t, fake.checkField(fakeTokens, &tokenID, "canReadAllFileUploads", true)(nil))
+	assert.Equal(t, 1, fake.count(fakeTokens))
+}
+
+func TestGetProjectToken(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeAPI(t)
+	client := fake.client()
+	projectID, token := testProjectTokenFixture(t, client)
+
+	// The token is read by ID without its value, the short-lived token is deleted afterwards
+	detail, err := client.getProjectToken(ctx, projectID, token.GetId())
+	require.NoError(t, err)
+	require.NotNil(t, detail)
+	assert.Equal(t, token.GetId(), detail.ID)
+	assert.Equal(t, "tf-test-token", detail.Description)
+	assert.Equal(t, 1, fake.count(fakeTokens))
+
+	// A revoked token is returned as nil
+	fake.remove(fakeTokens, token.GetId())
+	detail, err = client.getProjectToken(ctx, projectID, token.GetId())
+	require.NoError(t, err)
+	assert.Nil(t, detail)
+	assert.Equal(t, 0, fake.count(fakeTokens))
+}
+
+func TestDeleteProjectToken(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeAPI(t)
+	client := fake.client()
+	projectID, token := testProjectTokenFixture(t, client)
+
+	// The token is deleted by ID without its value, together with the short-lived token
+	require.NoError(t, client.deleteProjectToken(ctx, projectID, token.GetId()))
+	assert.Equal(t, 0, fake.count(fakeTokens))
+
+	// A token which does not exist anymore is considered deleted
+	require.NoError(t, client.deleteProjectToken(ctx, projectID, token.GetId()))
+}
+
+// testProjectTokenFixture creates a project with a storage token in the fake API.
+func testProjectTokenFixture(t *testing.T, client *Client) (string, *management.CreateStorageToken201Response) {
+	t.Helper()
+	ctx := context.Background()
+	api := client.API
+
+	maintainer, _, err := api.MaintainersAPI.CreateAMaintainer(ctx).CreateAMaintainerRequest(management.CreateAMaintainerRequest{Name: "tf-test-maintainer"}).Execute()
+	require.NoError(t, err)
+	organization, _, err := api.OrganizationsAPI.CreateAnOrganization(ctx, maintainer.GetId()).CreateAnOrganizationRequest(management.CreateAnOrganizationRequest{
+		Name: management.PtrString("tf-test-organization"),
+	}).Execute()
+	require.NoError(t, err)
+	project, _, err := api.ProjectsAPI.AddAProject(ctx, strconv.FormatInt(int64(organization.GetId()), 10)).AddAProjectRequest(management.AddAProjectRequest{Name: "tf-test-project", Type: "demo"}).Execute()
+	require.NoError(t, err)
+	projectID := strconv.FormatInt(int64(project.GetId()), 10)
+	token, _, err := api.ProjectsAPI.CreateStorageToken(ctx, projectID).CreateStorageTokenRequest(management.CreateStorageTokenRequest{Description: "tf-test-token"}).Execute()
+	require.NoError(t, err)
+	return projectID, token
+}
+
+func TestTokenRotationTime(t *testing.T) {
+	created := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC), tokenRotationTime(created, 90, 0))
+	assert.Equal(t, time.Date(2025, 3, 25, 12, 0, 0, 0, time.UTC), tokenRotationTime(created, 90, 7))
+}
+
+func testAccProjectTokenRotationConfig(rotationDays, rotateBeforeDays int) string {
+	return testAccProjectConfig("tf-test-project") + fmt.Sprintf(`
+resource "keboola-management_project_token" "test" {
+  project_id         = keboola-management_project.test.id
+  description        = "tf-test-rotated-token"
+  rotation_days      = %d
+  rotate_before_days = %d
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+`, rotationDays, rotateBeforeDays)
+}
+
+func testAccProjectTokenBucketPermissionsConfig(permissions string) string {
+	return testAccProjectConfig("tf-test-project") + fmt.Sprintf(`
+resource "keboola-management_project_token" "test" {
+  project_id  = keboola-management_project.test.id
+  description = "tf-test-bucket-token"
+
+  bucket_permissions = {%s
+  }
+}
+`, permissions)
+}
+
+func testAccProjectTokenUpdateConfig(permissions string, expiresIn int) string {
+	return testAccProjectConfig("tf-test-project") + fmt.Sprintf(`
+resource "keboola-management_project_token" "test" {
+  project_id  = keboola-management_project.test.id
+  description = "tf-test-updated-token"
+  expires_in  = %d%s
+}
+`, expiresIn, permissions)
+}
+
+func testAccProjectTokenPGPConfig(pgpKey string) string {
+	return testAccProjectConfig("tf-test-project") + fmt.Sprintf(`
+resource "keboola-management_project_token" "test" {
+  project_id  = keboola-management_project.test.id
+  description = "tf-test-encrypted-token"
+  expires_in  = 3600
+  pgp_key     = %q
+}
+`, pgpKey)
+}
+
+func testAccProjectTokenConfig(description string) string {
+	return testAccProjectConfig("tf-test-project") + fmt.Sprintf(`
+resource "keboola-management_project_token" "test" {
+  project_id         = keboola-management_project.test.id
+  description        = %q
+  can_manage_buckets = true
+  expires_in         = 3600
+}
+`, description)
+}
+
+// testAccCheckTokenCount checks the number of storage tokens in the fake API.
+func testAccCheckTokenCount(fake *fakeAPI, expected int) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		if n := fake.count(fakeTokens); n != expected {
+			return fmt.Errorf("expected %d tokens in the fake API, got %d", expected, n)
+		}
+		return nil
+	}
+}
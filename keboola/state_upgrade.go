@@ -0,0 +1,63 @@
+package keboola
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// upgradeInt64 converts a number which the previous schema version stored as a string.
+// A null or empty string is converted to null.
+func upgradeInt64(value types.String, attr string, diags *diag.Diagnostics) types.Int64 {
+	if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+		return types.Int64Null()
+	}
+	n, err := strconv.ParseInt(value.ValueString(), 10, 64)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(attr),
+			"Error upgrading state",
+			"Could not convert "+attr+" to a number: "+err.Error(),
+		)
+		return types.Int64Null()
+	}
+	return types.Int64Value(n)
+}
+
+// upgradeBool converts a boolean which the previous schema version stored as a string.
+// A null or empty string is converted to null.
+func upgradeBool(value types.String, attr string, diags *diag.Diagnostics) types.Bool {
+	if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+		return types.BoolNull()
+	}
+	b, err := strconv.ParseBool(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(attr),
+			"Error upgrading state",
+			"Could not convert "+attr+" to a boolean: "+err.Error(),
+		)
+		return types.BoolNull()
+	}
+	return types.BoolValue(b)
+}
+
+// formatInt64 formats a number for the API requests which expect it as a string.
+func formatInt64(value types.Int64) *string {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	s := strconv.FormatInt(value.ValueInt64(), 10)
+	return &s
+}
+
+// formatBool formats a boolean for the API requests which expect it as a string.
+func formatBool(value types.Bool) *string {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	s := strconv.FormatBool(value.ValueBool())
+	return &s
+}
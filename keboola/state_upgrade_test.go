@@ -0,0 +1,156 @@
+package keboola
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upgradeTestState upgrades a version 0 state of the resource, the values are strings and the attributes not in values are null.
+func upgradeTestState(t *testing.T, r resource.Resource, values map[string]string) (tfsdk.State, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+	upgrader := r.(resource.ResourceWithUpgradeState).UpgradeState(ctx)[0]
+	require.NotNil(t, upgrader.PriorSchema)
+
+	priorType := upgrader.PriorSchema.Type().TerraformType(ctx).(tftypes.Object)
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range priorType.AttributeTypes {
+		if v, ok := values[name]; ok {
+			attributes[name] = tftypes.NewValue(tftypes.String, v)
+		} else {
+			attributes[name] = tftypes.NewValue(attributeType, nil)
+		}
+	}
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	require.Equal(t, int64(1), schemaResp.Schema.Version)
+
+	prior := tfsdk.State{Schema: *upgrader.PriorSchema, Raw: tftypes.NewValue(priorType, attributes)}
+	resp := &resource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	upgrader.StateUpgrader(ctx, resource.UpgradeStateRequest{State: &prior}, resp)
+	return resp.State, resp.Diagnostics
+}
+
+func TestOrganizationResourceUpgradeState(t *testing.T) {
+	state, diags := upgradeTestState(t, NewOrganizationResource(), map[string]string{
+		"id":              "10",
+		"name":            "tf-test-organization",
+		"maintainer_id":   "5",
+		"allow_auto_join": "false",
+		"crm_id":          "crm-1",
+		"mfa_required":    "",
+	})
+	require.False(t, diags.HasError(), diags)
+
+	var model organizationResourceModel
+	require.False(t, state.Get(context.Background(), &model).HasError())
+	assert.Equal(t, organizationResourceModel{
+		ID:                      types.StringValue("10"),
+		Name:                    types.StringValue("tf-test-organization"),
+		MaintainerID:            types.Int64Value(5),
+		AllowAutoJoin:           types.BoolValue(false),
+		CrmID:                   types.StringValue("crm-1"),
+		ActivityCenterProjectID: types.Int64Null(),
+		MfaRequired:             types.BoolNull(),
+	}, model)
+
+	_, diags = upgradeTestState(t, NewOrganizationResource(), map[string]string{"id": "10", "maintainer_id": "5", "mfa_required": "yes"})
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags.Errors()[0].Detail(), "Could not convert mfa_required to a boolean")
+}
+
+func TestProjectResourceUpgradeState(t *testing.T) {
+	state, diags := upgradeTestState(t, NewProjectResource(), map[string]string{
+		"id":                          "20",
+		"name":                        "tf-test-project",
+		"organization_id":             "10",
+		"type":                        "demo",
+		"data_retention_time_in_days": "7",
+	})
+	require.False(t, diags.HasError(), diags)
+
+	var model projectResourceModel
+	require.False(t, state.Get(context.Background(), &model).HasError())
+	assert.Equal(t, projectResourceModel{
+		ID:                      types.StringValue("20"),
+		Name:                    types.StringValue("tf-test-project"),
+		OrganizationID:          types.Int64Value(10),
+		Type:                    types.StringValue("demo"),
+		DefaultBackend:          types.StringNull(),
+		DataRetentionTimeInDays: types.Int64Value(7),
+	}, model)
+
+	_, diags = upgradeTestState(t, NewProjectResource(), map[string]string{"id": "20", "organization_id": "my-organization"})
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags.Errors()[0].Detail(), "Could not convert organization_id to a number")
+}
+
+func TestMaintainerResourceUpgradeState(t *testing.T) {
+	state, diags := upgradeTestState(t, NewMaintainerResource(), map[string]string{
+		"id":                              "5",
+		"name":                            "tf-test-maintainer",
+		"default_connection_snowflake_id": "12",
+		"default_file_storage_id":         "3",
+		"zendesk_url":                     "https://support.example.com",
+	})
+	require.False(t, diags.HasError(), diags)
+
+	var model maintainerResourceModel
+	require.False(t, state.Get(context.Background(), &model).HasError())
+	assert.Equal(t, maintainerResourceModel{
+		ID:                           types.StringValue("5"),
+		Name:                         types.StringValue("tf-test-maintainer"),
+		DefaultConnectionRedshiftID:  types.Int64Null(),
+		DefaultConnectionSnowflakeID: types.Int64Value(12),
+		DefaultConnectionSynapseID:   types.Int64Null(),
+		DefaultConnectionExasolID:    types.Int64Null(),
+		DefaultConnectionTeradataID:  types.Int64Null(),
+		DefaultFileStorageID:         types.Int64Value(3),
+		ZendeskURL:                   types.StringValue("https://support.example.com"),
+	}, model)
+}
+
+func TestBackendResourceUpgradeState(t *testing.T) {
+	state, diags := upgradeTestState(t, NewBackendResource(), map[string]string{
+		"id":                           "30",
+		"backend":                      "synapse",
+		"host":                         "example.sql.azuresynapse.net",
+		"username":                     "keboola",
+		"password":                     "secret",
+		"region":                       "westeurope",
+		"owner":                        "keboola",
+		"database":                     "KEBOOLA",
+		"use_synapse_managed_identity": "true",
+	})
+	require.False(t, diags.HasError(), diags)
+
+	var model backendResourceModel
+	require.False(t, state.Get(context.Background(), &model).HasError())
+	assert.Equal(t, backendResourceModel{
+		ID:                        types.StringValue("30"),
+		Backend:                   types.StringValue("synapse"),
+		Host:                      types.StringValue("example.sql.azuresynapse.net"),
+		Username:                  types.StringValue("keboola"),
+		Password:                  types.StringNull(),
+		PasswordVersion:           types.Int64Null(),
+		Region:                    types.StringValue("westeurope"),
+		Owner:                     types.StringValue("keboola"),
+		Warehouse:                 types.StringNull(),
+		Database:                  types.StringValue("KEBOOLA"),
+		UseSynapseManagedIdentity: types.BoolValue(true),
+		UseDynamicBackends:        types.BoolNull(),
+	}, model)
+
+	_, diags = upgradeTestState(t, NewBackendResource(), map[string]string{"id": "30", "backend": "synapse", "use_synapse_managed_identity": "yes"})
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags.Errors()[0].Detail(), "Could not convert use_synapse_managed_identity to a boolean")
+}
@@ -0,0 +1,454 @@
+package keboola
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/keboola/keboola-sdk-go/v2/pkg/keboola/management"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAccNamePrefix marks objects created by acceptance tests, sweepers delete only objects with this prefix.
+// Backends have no name, they are matched by the owner instead.
+const testAccNamePrefix = "tf-test-"
+
+// TestMain enables the -sweep flag, e.g. go test ./keboola -v -sweep=all
+// Sweepers use the KBC_API_URL (or KBC_HOSTNAME_SUFFIX) and KBC_MANAGE_TOKEN (or KBC_MANAGE_TOKEN_FILE) environment variables.
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+// File storages are not swept, the API does not support their deletion.
+func init() {
+	resource.AddTestSweepers("keboola-management_manage_token", &resource.Sweeper{
+		Name: "keboola-management_manage_token",
+		F:    sweeperFunc(sweepManageTokens),
+	})
+	resource.AddTestSweepers("keboola-management_project_token", &resource.Sweeper{
+		Name: "keboola-management_project_token",
+		F:    sweeperFunc(sweepProjectTokens),
+	})
+	resource.AddTestSweepers("keboola-management_project_invitation", &resource.Sweeper{
+		Name: "keboola-management_project_invitation",
+		F:    sweeperFunc(sweepProjectInvitations),
+	})
+	resource.AddTestSweepers("keboola-management_project_feature", &resource.Sweeper{
+		Name: "keboola-management_project_feature",
+		F:    sweeperFunc(sweepProjectFeatures),
+	})
+	resource.AddTestSweepers("keboola-management_project", &resource.Sweeper{
+		Name: "keboola-management_project",
+		Dependencies: []string{
+			"keboola-management_project_token",
+			"keboola-management_project_invitation",
+			"keboola-management_project_feature",
+		},
+		F: sweeperFunc(sweepProjects),
+	})
+	resource.AddTestSweepers("keboola-management_organization", &resource.Sweeper{
+		Name:         "keboola-management_organization",
+		Dependencies: []string{"keboola-management_project"},
+		F:            sweeperFunc(sweepOrganizations),
+	})
+	resource.AddTestSweepers("keboola-management_maintainer", &resource.Sweeper{
+		Name:         "keboola-management_maintainer",
+		Dependencies: []string{"keboola-management_organization"},
+		F:            sweeperFunc(sweepMaintainers),
+	})
+	resource.AddTestSweepers("keboola-management_backend", &resource.Sweeper{
+		Name: "keboola-management_backend",
+		F: sweeperFunc(func(ctx context.Context, client *Client) error {
+			return sweepBackends(ctx, client, func(backend string) bool { return backend != "bigquery" })
+		}),
+	})
+	resource.AddTestSweepers("keboola-management_backend_bigquery", &resource.Sweeper{
+		Name: "keboola-management_backend_bigquery",
+		F: sweeperFunc(func(ctx context.Context, client *Client) error {
+			return sweepBackends(ctx, client, func(backend string) bool { return backend == "bigquery" })
+		}),
+	})
+}
+
+// sweeperFunc adapts a sweep function to the sweeper signature.
+// The region is not used, the stack is selected by the provider environment variables.
+func sweeperFunc(sweep func(ctx context.Context, client *Client) error) func(string) error {
+	return func(_ string) error {
+		client, err := sweeperClient()
+		if err != nil {
+			return err
+		}
+		return sweep(context.Background(), client)
+	}
+}
+
+func sweeperClient() (*Client, error) {
+	// The token and the stack are resolved from the environment variables like in the provider
+	token, err := tokenFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, fmt.Errorf("%s or %s must be set for sweepers", KbcToken, KbcTokenFile)
+	}
+	apiURL, hostnameSuffix := os.Getenv(KbcAPIURL), os.Getenv(KbcHostnameSuffix)
+	if apiURL == "" && hostnameSuffix == "" {
+		return nil, fmt.Errorf("%s or %s must be set for sweepers", KbcAPIURL, KbcHostnameSuffix)
+	}
+	apiURL, err = stackAPIURL(apiURL, hostnameSuffix)
+	if err != nil {
+		return nil, err
+	}
+	return newClient(apiURL, token, &http.Client{Transport: http.DefaultTransport}), nil
+}
+
+// sweepObject is an item of the maintainers and organizations lists, which the SDK does not decode.
+type sweepObject struct {
+	ID   float32 `json:"id"`
+	Name string  `json:"name"`
+}
+
+func (o sweepObject) isTestObject() bool {
+	return strings.HasPrefix(o.Name, testAccNamePrefix)
+}
+
+func decodeSweepObjects(resp *http.Response, err error) ([]sweepObject, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var objects []sweepObject
+	if err := json.NewDecoder(resp.Body).Decode(&objects); err != nil {
+		return nil, fmt.Errorf("could not decode the list: %w", err)
+	}
+	return objects, nil
+}
+
+func listSweepMaintainers(ctx context.Context, client *Client) ([]sweepObject, error) {
+	return decodeSweepObjects(client.API.MaintainersAPI.ListMaintainers(ctx).Execute())
+}
+
+// listSweepOrganizations returns organizations of all maintainers, a test organization may belong to any of them.
+func listSweepOrganizations(ctx context.Context, client *Client) ([]sweepObject, error) {
+	maintainers, err := listSweepMaintainers(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("could not list maintainers: %w", err)
+	}
+	var result []sweepObject
+	for _, maintainer := range maintainers {
+		organizations, err := decodeSweepObjects(client.API.OrganizationsAPI.ListMaintainersOrganizations(ctx, maintainer.ID).Execute())
+		if err != nil {
+			return nil, fmt.Errorf("could not list organizations of maintainer %v: %w", maintainer.ID, err)
+		}
+		result = append(result, organizations...)
+	}
+	return result, nil
+}
+
+// listSweepProjects returns IDs of test projects in all organizations.
+func listSweepProjects(ctx context.Context, client *Client) ([]string, error) {
+	organizations, err := listSweepOrganizations(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, organization := range organizations {
+		detail, _, err := client.API.OrganizationsAPI.RetrieveAnOrganization(ctx, organization.ID).Execute()
+		if err != nil {
+			return nil, fmt.Errorf("could not read organization %v: %w", organization.ID, err)
+		}
+		for _, project := range detail.Projects {
+			if strings.HasPrefix(project.GetName(), testAccNamePrefix) {
+				result = append(result, strconv.FormatInt(int64(project.GetId()), 10))
+			}
+		}
+	}
+	return result, nil
+}
+
+// sweepManageTokens deletes test application tokens of the Manage API.
+func sweepManageTokens(ctx context.Context, client *Client) error {
+	var tokens []manageToken
+	if err := client.sendManageRequest(ctx, http.MethodGet, "/manage/tokens", nil, &tokens); err != nil {
+		return fmt.Errorf("could not list manage tokens: %w", err)
+	}
+
+	var errs []error
+	for _, token := range tokens {
+		if !strings.HasPrefix(token.Description, testAccNamePrefix) {
+			continue
+		}
+		if err := client.deleteManageToken(ctx, token.ID.String()); err != nil && !isManageTokenGone(err) {
+			errs = append(errs, fmt.Errorf("could not delete manage token %s: %w", token.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sweepProjectTokens deletes test tokens of test projects. The Storage API lists tokens only for a token
+// of the same project, so a short-lived token is created for each project and deleted at the end.
+func sweepProjectTokens(ctx context.Context, client *Client) error {
+	projects, err := listSweepProjects(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, projectID := range projects {
+		canManageTokens := true
+		expiresIn := float32(3600)
+		sweeperToken, _, err := client.API.ProjectsAPI.CreateStorageToken(ctx, projectID).CreateStorageTokenRequest(management.CreateStorageTokenRequest{
+			Description:     testAccNamePrefix + "sweeper",
+			CanManageTokens: &canManageTokens,
+			ExpiresIn:       &expiresIn,
+		}).Execute()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not create a token in project %s: %w", projectID, err))
+			continue
+		}
+
+		storageAPI, err := client.NewStorageAPI(ctx, sweeperToken.GetToken())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not create a Storage API client for project %s: %w", projectID, err))
+			continue
+		}
+		tokens, err := storageAPI.ListTokensRequest().Send(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not list tokens of project %s: %w", projectID, err))
+		} else {
+			for _, token := range *tokens {
+				if token.ID == sweeperToken.GetId() || !strings.HasPrefix(token.Description, testAccNamePrefix) {
+					continue
+				}
+				if _, err := storageAPI.DeleteTokenRequest(token.ID).Send(ctx); err != nil {
+					errs = append(errs, fmt.Errorf("could not delete token %s of project %s: %w", token.ID, projectID, err))
+				}
+			}
+		}
+		if _, err := storageAPI.DeleteTokenRequest(sweeperToken.GetId()).Send(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("could not delete the sweeper token of project %s: %w", projectID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sweepProjectInvitations cancels invitations of test users in test projects.
+func sweepProjectInvitations(ctx context.Context, client *Client) error {
+	projects, err := listSweepProjects(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, projectID := range projects {
+		invitations, _, err := client.API.ProjectsAPI.ListProjectInvitations(ctx, projectID).Execute()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not list invitations of project %s: %w", projectID, err))
+			continue
+		}
+		for _, invitation := range invitations {
+			if !strings.HasPrefix(invitation.User.GetEmail(), testAccNamePrefix) {
+				continue
+			}
+			invitationID := strconv.FormatInt(int64(invitation.Id), 10)
+			if _, err := client.API.ProjectsAPI.CancelProjectInvitation(ctx, projectID, invitationID).Execute(); err != nil {
+				errs = append(errs, fmt.Errorf("could not cancel invitation %s of project %s: %w", invitationID, projectID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sweepProjectFeatures removes all features from test projects.
+func sweepProjectFeatures(ctx context.Context, client *Client) error {
+	projects, err := listSweepProjects(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, projectID := range projects {
+		project, _, err := client.API.ProjectsAPI.ProjectDetail(ctx, projectID).Execute()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not read project %s: %w", projectID, err))
+			continue
+		}
+		for _, feature := range project.Features {
+			name, ok := feature.(string)
+			if !ok {
+				continue
+			}
+			if _, err := client.API.SUPERFeaturesAPI.RemoveAProjectFeature(ctx, projectID, name).Execute(); err != nil {
+				errs = append(errs, fmt.Errorf("could not remove feature %s from project %s: %w", name, projectID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sweepProjects(ctx context.Context, client *Client) error {
+	projects, err := listSweepProjects(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, projectID := range projects {
+		if _, err := client.API.ProjectsAPI.DeleteAProject(ctx, projectID).Execute(); err != nil {
+			errs = append(errs, fmt.Errorf("could not delete project %s: %w", projectID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sweepOrganizations(ctx context.Context, client *Client) error {
+	organizations, err := listSweepOrganizations(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, organization := range organizations {
+		if !organization.isTestObject() {
+			continue
+		}
+		if _, err := client.API.OrganizationsAPI.DeleteAnOrganization(ctx, organization.ID).Execute(); err != nil {
+			errs = append(errs, fmt.Errorf("could not delete organization %v: %w", organization.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sweepMaintainers(ctx context.Context, client *Client) error {
+	maintainers, err := listSweepMaintainers(ctx, client)
+	if err != nil {
+		return fmt.Errorf("could not list maintainers: %w", err)
+	}
+
+	var errs []error
+	for _, maintainer := range maintainers {
+		if !maintainer.isTestObject() {
+			continue
+		}
+		if _, err := client.API.MaintainersAPI.DeleteAMaintainer(ctx, int32(maintainer.ID)).Execute(); err != nil {
+			errs = append(errs, fmt.Errorf("could not delete maintainer %v: %w", maintainer.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sweepBackends deletes backends owned by tests, matching is the filter of the backend type.
+func sweepBackends(ctx context.Context, client *Client, matching func(backend string) bool) error {
+	backends, _, err := client.API.SUPERStorageBackendsManagementAPI.ListBackends(ctx).Execute()
+	if err != nil {
+		return fmt.Errorf("could not list backends: %w", err)
+	}
+
+	var errs []error
+	for _, item := range backends {
+		backend, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		owner, _ := backend["owner"].(string)
+		backendType, _ := backend["backend"].(string)
+		if !strings.HasPrefix(owner, testAccNamePrefix) || !matching(backendType) {
+			continue
+		}
+		backendID := stringValue(backend["id"])
+		if _, err := client.API.SUPERStorageBackendsManagementAPI.DeleteBackend(ctx, backendID).Execute(); err != nil {
+			errs = append(errs, fmt.Errorf("could not delete backend %s: %w", backendID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func TestSweepers(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeAPI(t)
+	client := fake.client()
+	api := client.API
+
+	// A test project in an organization of a regular maintainer, and a regular project next to it
+	maintainer, _, err := api.MaintainersAPI.CreateAMaintainer(ctx).CreateAMaintainerRequest(management.CreateAMaintainerRequest{Name: "production"}).Execute()
+	require.NoError(t, err)
+	organization, _, err := api.OrganizationsAPI.CreateAnOrganization(ctx, maintainer.GetId()).CreateAnOrganizationRequest(management.CreateAnOrganizationRequest{
+		Name: management.PtrString(testAccNamePrefix + "organization"),
+	}).Execute()
+	require.NoError(t, err)
+	organizationID := strconv.FormatInt(int64(organization.GetId()), 10)
+	testProject, _, err := api.ProjectsAPI.AddAProject(ctx, organizationID).AddAProjectRequest(management.AddAProjectRequest{Name: testAccNamePrefix + "project", Type: "demo"}).Execute()
+	require.NoError(t, err)
+	testProjectID := strconv.FormatInt(int64(testProject.GetId()), 10)
+	regularProject, _, err := api.ProjectsAPI.AddAProject(ctx, organizationID).AddAProjectRequest(management.AddAProjectRequest{Name: "regular", Type: "demo"}).Execute()
+	require.NoError(t, err)
+	regularProjectID := strconv.FormatInt(int64(regularProject.GetId()), 10)
+
+	_, _, err = api.ProjectsAPI.CreateStorageToken(ctx, testProjectID).CreateStorageTokenRequest(management.CreateStorageTokenRequest{Description: testAccNamePrefix + "token"}).Execute()
+	require.NoError(t, err)
+	_, _, err = api.ProjectsAPI.InviteAUserToAProject(ctx, testProjectID).InviteAUserToAProjectRequest(management.InviteAUserToAProjectRequest{Email: testAccNamePrefix + "user@example.com"}).Execute()
+	require.NoError(t, err)
+	_, _, err = api.SUPERFeaturesAPI.AddAProjectFeature(ctx, testProjectID).AddAProjectFeatureRequest(management.AddAProjectFeatureRequest{Feature: "feature"}).Execute()
+	require.NoError(t, err)
+	_, _, err = api.SUPERStorageBackendsManagementAPI.CreateANewBackend(ctx).CreateANewBackendRequest(management.CreateANewBackendRequest{
+		Backend: "snowflake", Host: "host", Username: "user", Password: "secret", Region: "us-east-1", Owner: testAccNamePrefix + "owner",
+	}).Execute()
+	require.NoError(t, err)
+
+	// Sweepers run in the dependency order
+	for _, sweep := range []func(context.Context, *Client) error{
+		sweepProjectTokens,
+		sweepProjectInvitations,
+		sweepProjectFeatures,
+		sweepProjects,
+	} {
+		require.NoError(t, sweep(ctx, client))
+	}
+	require.NoError(t, sweepBackends(ctx, client, func(string) bool { return true }))
+
+	assert.Equal(t, 0, fake.count(fakeTokens), "expected all tokens to be deleted")
+	assert.Equal(t, 0, fake.count(fakeInvitations), "expected all invitations to be cancelled")
+	assert.False(t, fake.exists(fakeProjects, testProjectID), "expected the test project to be deleted")
+	assert.True(t, fake.exists(fakeProjects, regularProjectID), "expected the regular project to be kept")
+	assert.Equal(t, 0, fake.count(fakeBackends), "expected the test backend to be deleted")
+
+	// The organization still contains the regular project, so it cannot be deleted yet
+	assert.Error(t, sweepOrganizations(ctx, client), "expected an error deleting a non-empty organization")
+	fake.remove(fakeProjects, regularProjectID)
+	require.NoError(t, sweepOrganizations(ctx, client))
+	require.NoError(t, sweepMaintainers(ctx, client))
+	assert.Equal(t, 0, fake.count(fakeOrganizations), "expected the test organization to be deleted")
+	assert.Equal(t, 1, fake.count(fakeMaintainers), "expected the regular maintainer to be kept")
+}
+
+func TestSweeperClient(t *testing.T) {
+	t.Setenv(KbcToken, "")
+	t.Setenv(KbcTokenFile, "")
+	t.Setenv(KbcAPIURL, "")
+	t.Setenv(KbcHostnameSuffix, "keboola.com")
+	_, err := sweeperClient()
+	assert.ErrorContains(t, err, "KBC_MANAGE_TOKEN or KBC_MANAGE_TOKEN_FILE must be set")
+
+	// The token is read from the file, the API URL overrides the hostname suffix
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("file-token\n"), 0o600))
+	t.Setenv(KbcTokenFile, path)
+	t.Setenv(KbcAPIURL, "http://localhost:8080/")
+	client, err := sweeperClient()
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", client.APIURL)
+	assert.Equal(t, "file-token", client.API.GetConfig().DefaultHeader["X-KBC-ManageApiToken"])
+
+	t.Setenv(KbcAPIURL, "localhost:8080")
+	_, err = sweeperClient()
+	assert.ErrorContains(t, err, "api_url must be an absolute http or https URL")
+}
@@ -0,0 +1,294 @@
+package keboola
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+)
+
+// redactedValue replaces secrets in logged headers and bodies.
+const redactedValue = "***"
+
+// sensitiveHeaders are never logged in plain text.
+var sensitiveHeaders = []string{
+	"X-KBC-ManageApiToken",
+	"X-StorageApi-Token",
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// sensitiveFields are JSON body fields masked in logs, compared in lower case without "_" and "-".
+var sensitiveFields = map[string]bool{
+	"password":   true,
+	"awssecret":  true,
+	"accountkey": true,
+	"privatekey": true,
+	"token":      true,
+	"secret":     true,
+	"passphrase": true,
+}
+
+// transportConfig holds the TLS and proxy settings of the provider.
+type transportConfig struct {
+	// CACertPEM is a PEM encoded CA bundle trusted in addition to the system roots
+	CACertPEM []byte
+	// InsecureSkipVerify disables TLS certificate verification
+	InsecureSkipVerify bool
+	// ProxyURL overrides the proxy taken from the environment
+	ProxyURL *url.URL
+}
+
+// newBaseTransport creates the HTTP transport used for all API calls of the provider.
+func newBaseTransport(cfg transportConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if len(cfg.CACertPEM) > 0 || cfg.InsecureSkipVerify {
+		tlsConfig := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint: gosec // explicitly requested by the user
+		}
+		if len(cfg.CACertPEM) > 0 {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(cfg.CACertPEM) {
+				return nil, errors.New("no valid PEM certificates found in the CA bundle")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if cfg.ProxyURL != nil {
+		transport.Proxy = http.ProxyURL(cfg.ProxyURL)
+	}
+
+	return transport, nil
+}
+
+// rateLimitTransport throttles requests sent through the shared Management API client.
+// A single instance is shared by all resources of one provider instance, so the limits
+// apply to the whole apply run regardless of Terraform parallelism.
+type rateLimitTransport struct {
+	next      http.RoundTripper
+	limiter   *rate.Limiter
+	semaphore chan struct{}
+}
+
+// newRateLimitTransport wraps next with a request rate limit and a concurrency cap.
+// A zero value disables the corresponding limit.
+func newRateLimitTransport(next http.RoundTripper, requestsPerSecond float64, maxConcurrentRequests int64) http.RoundTripper {
+	if requestsPerSecond <= 0 && maxConcurrentRequests <= 0 {
+		return next
+	}
+
+	t := &rateLimitTransport{next: next}
+	if requestsPerSecond > 0 {
+		// Allow a burst of at least one request so that low rates still make progress
+		burst := int(requestsPerSecond)
+		if burst < 1 {
+			burst = 1
+		}
+		t.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+	}
+	if maxConcurrentRequests > 0 {
+		t.semaphore = make(chan struct{}, maxConcurrentRequests)
+	}
+	return t
+}
+
+// RoundTrip waits for a free slot and a rate limit token before sending the request.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if t.semaphore != nil {
+		select {
+		case t.semaphore <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release := t.releaseFunc()
+
+	if t.limiter != nil {
+		if err := t.limiter.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		release()
+		return resp, err
+	}
+
+	// Keep the slot until the response body is consumed
+	resp.Body = &releaseOnCloseBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releaseFunc returns an idempotent function freeing the acquired concurrency slot.
+func (t *rateLimitTransport) releaseFunc() func() {
+	if t.semaphore == nil {
+		return func() {}
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-t.semaphore })
+	}
+}
+
+// releaseOnCloseBody frees the concurrency slot once the response body is closed.
+type releaseOnCloseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseOnCloseBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
+
+// loggingTransport logs every API call via tflog. A summary of each call is logged at DEBUG level,
+// headers and bodies at TRACE level, with tokens and credentials masked.
+// The verbosity follows TF_LOG_PROVIDER as the provider logger is carried in the request context.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func newLoggingTransport(next http.RoundTripper) http.RoundTripper {
+	return &loggingTransport{next: next}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := tflog.SetField(req.Context(), "http_request_id", newRequestID())
+	ctx = tflog.SetField(ctx, "http_method", req.Method)
+	ctx = tflog.SetField(ctx, "http_path", req.URL.Path)
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		tflog.Trace(ctx, "Sending API request", map[string]interface{}{
+			"http_request_headers": redactHeaders(req.Header),
+			"http_request_body":    redactBody(body),
+		})
+	} else {
+		tflog.Trace(ctx, "Sending API request", map[string]interface{}{
+			"http_request_headers": redactHeaders(req.Header),
+		})
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	ctx = tflog.SetField(ctx, "http_duration_ms", time.Since(start).Milliseconds())
+	if err != nil {
+		tflog.Debug(ctx, "API request failed", map[string]interface{}{"error": err.Error()})
+		return resp, err
+	}
+
+	ctx = tflog.SetField(ctx, "http_status", resp.StatusCode)
+	tflog.Debug(ctx, "API request completed")
+
+	if resp.Body != nil {
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		tflog.Trace(ctx, "Received API response", map[string]interface{}{
+			"http_response_headers": redactHeaders(resp.Header),
+			"http_response_body":    redactBody(body),
+		})
+	}
+
+	return resp, nil
+}
+
+// newRequestID returns a random ID correlating the log lines of one API call.
+func newRequestID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(id)
+}
+
+// redactHeaders returns the headers as a map with sensitive values masked.
+func redactHeaders(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for name, values := range header {
+		result[name] = strings.Join(values, ", ")
+	}
+	for _, name := range sensitiveHeaders {
+		if header.Get(name) != "" {
+			result[http.CanonicalHeaderKey(name)] = redactedValue
+		}
+	}
+	return result
+}
+
+// redactBody returns the body with sensitive JSON fields masked.
+// Bodies which are not JSON are not logged, as they cannot be redacted reliably.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Sprintf("<%d bytes of non-JSON content omitted>", len(body))
+	}
+	redacted, err := json.Marshal(redactJSONValue(value))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes omitted>", len(body))
+	}
+	return string(redacted)
+}
+
+func redactJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitiveField(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactJSONValue(item)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSONValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isSensitiveField(key string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	return sensitiveFields[normalized]
+}
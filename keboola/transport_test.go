@@ -0,0 +1,95 @@
+package keboola
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitTransport(t *testing.T) {
+	t.Run("disabled limits return the wrapped transport", func(t *testing.T) {
+		assert.Equal(t, http.DefaultTransport, newRateLimitTransport(http.DefaultTransport, 0, 0))
+	})
+
+	t.Run("concurrency cap", func(t *testing.T) {
+		var inFlight, maxInFlight int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				seen := atomic.LoadInt32(&maxInFlight)
+				if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: newRateLimitTransport(http.DefaultTransport, 0, 2)}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := client.Get(server.URL)
+				if assert.NoError(t, err) {
+					resp.Body.Close()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+	})
+
+	t.Run("requests per second", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: newRateLimitTransport(http.DefaultTransport, 20, 0)}
+
+		start := time.Now()
+		for i := 0; i < 41; i++ {
+			resp, err := client.Get(server.URL)
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+			}
+		}
+
+		// A burst of 20 is allowed, the remaining 21 requests need at least one second
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	})
+}
+
+func TestRedactBody(t *testing.T) {
+	body := []byte(`{"name":"x","awsSecret":"s1","gcsCredentials":{"private_key":"s2","client_email":"e"},"items":[{"Password":"s3"}],"token":"s4"}`)
+	redacted := redactBody(body)
+
+	for _, secret := range []string{"s1", "s2", "s3", "s4"} {
+		assert.NotContains(t, redacted, `"`+secret+`"`)
+	}
+	assert.Contains(t, redacted, `"client_email":"e"`)
+	assert.Contains(t, redacted, `"name":"x"`)
+
+	assert.Equal(t, "<9 bytes of non-JSON content omitted>", redactBody([]byte("token=abc")))
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-KBC-ManageApiToken", "secret")
+	header.Set("Content-Type", "application/json")
+
+	redacted := redactHeaders(header)
+	assert.Equal(t, redactedValue, redacted["X-Kbc-Manageapitoken"])
+	assert.Equal(t, "application/json", redacted["Content-Type"])
+}
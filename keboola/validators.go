@@ -0,0 +1,43 @@
+package keboola
+
+import (
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// Project types accepted by the API.
+var projectTypes = []string{"production", "poc", "demo"}
+
+// Roles of a user in a project accepted by the API.
+var projectRoles = []string{"admin", "guest", "readOnly", "share"}
+
+// projectDefaultBackends returns the backends which can be the default backend of a project.
+func projectDefaultBackends() []string {
+	return append(backendEngineNames(), "bigquery")
+}
+
+var (
+	// numericIDRegexp matches the numeric IDs of the Management API objects.
+	numericIDRegexp = regexp.MustCompile(`^[0-9]+$`)
+	// emailRegexp matches an email address, the API validates it fully.
+	emailRegexp = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	// urlRegexp matches an absolute HTTP or HTTPS URL.
+	urlRegexp = regexp.MustCompile(`^https?://[^\s/]+\S*$`)
+)
+
+// numericIDValidators validates an attribute which references an object by its numeric ID.
+func numericIDValidators() []validator.String {
+	return []validator.String{stringvalidator.RegexMatches(numericIDRegexp, "must be a numeric ID, e.g. 123")}
+}
+
+// emailValidators validates an email address.
+func emailValidators() []validator.String {
+	return []validator.String{stringvalidator.RegexMatches(emailRegexp, "must be an email address, e.g. user@example.com")}
+}
+
+// urlValidators validates an HTTP or HTTPS URL.
+func urlValidators() []validator.String {
+	return []validator.String{stringvalidator.RegexMatches(urlRegexp, "must be an HTTP or HTTPS URL, e.g. https://example.com")}
+}